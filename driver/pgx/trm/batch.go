@@ -0,0 +1,62 @@
+package trm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrBatchUnsupported is returned by the results of SendBatch when the transaction cannot send batches.
+var ErrBatchUnsupported = errors.New("trm: transaction does not support batches")
+
+type batchSender interface {
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// SendBatch sends the statements queued in b within tx, the transaction a repository was bound to
+// by WithTx, in a single round trip, e.g. to insert all the items of an order at once instead of
+// one statement each. Read the results in queue order and close them before the next statement of
+// the transaction; a failed statement aborts the transaction, as any other.
+//
+// tx must be a pgx.Tx, as InTx passes, or another Transaction with a SendBatch method, e.g. that of
+// pgxmock; otherwise every result fails with ErrBatchUnsupported.
+func SendBatch(ctx context.Context, tx Transaction, b *pgx.Batch) pgx.BatchResults {
+	sender, ok := tx.(batchSender)
+	if !ok {
+		return failedBatch{err: fmt.Errorf("send batch: %w", ErrBatchUnsupported)}
+	}
+
+	return sender.SendBatch(ctx, b)
+}
+
+// failedBatch are the results of a batch that was not sent.
+type failedBatch struct {
+	err error
+}
+
+func (slf failedBatch) Exec() (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, slf.err
+}
+
+func (slf failedBatch) Query() (pgx.Rows, error) {
+	return nil, slf.err
+}
+
+func (slf failedBatch) QueryRow() pgx.Row {
+	return failedRow(slf)
+}
+
+func (slf failedBatch) Close() error {
+	return slf.err
+}
+
+type failedRow struct {
+	err error
+}
+
+func (slf failedRow) Scan(...any) error {
+	return slf.err
+}
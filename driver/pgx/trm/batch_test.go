@@ -0,0 +1,83 @@
+package trm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v5"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/pgx/trm"
+)
+
+type txRepo struct {
+	tx trm.Transaction
+}
+
+func (slf *txRepo) WithTx(tx trm.Transaction) *txRepo {
+	return &txRepo{tx: tx}
+}
+
+type SendBatch struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock pgxmock.PgxPoolIface
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *SendBatch) SetupTest() {
+	mock, err := pgxmock.NewPool()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(mock, &txRepo{})
+}
+
+func (slf *SendBatch) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *SendBatch) TestWithinTransaction() {
+	slf.mock.ExpectBeginTx(pgx.TxOptions{})
+	eb := slf.mock.ExpectBatch()
+	eb.ExpectExec("INSERT INTO orders").WithArgs("apple").WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	eb.ExpectExec("INSERT INTO orders").WithArgs("pear").WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	slf.mock.ExpectCommit()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		b := &pgx.Batch{}
+		for _, item := range []string{"apple", "pear"} {
+			b.Queue("INSERT INTO orders (item) VALUES ($1)", item)
+		}
+
+		results := trm.SendBatch(slf.ctx, r.tx, b)
+		for range b.Len() {
+			tag, err := results.Exec()
+			slf.Require().NoError(err)
+			slf.Equal(int64(1), tag.RowsAffected())
+		}
+
+		return results.Close()
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *SendBatch) TestUnsupportedTransaction() {
+	b := &pgx.Batch{}
+	b.Queue("SELECT 1")
+
+	results := trm.SendBatch(slf.ctx, struct{ trm.Transaction }{}, b)
+
+	_, err := results.Exec()
+	slf.Require().ErrorIs(err, trm.ErrBatchUnsupported)
+	slf.Require().ErrorIs(results.QueryRow().Scan(), trm.ErrBatchUnsupported)
+	slf.Require().ErrorIs(results.Close(), trm.ErrBatchUnsupported)
+}
+
+func TestSendBatch(t *testing.T) {
+	suite.Run(t, new(SendBatch))
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// Query is the set of methods repositories use to execute statements.
+// It is implemented both by the connection and by Transaction,
+// so a repository stores a single Query field and WithTx replaces it with the transaction.
 type Query interface {
 	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
 	Prepare(ctx context.Context, name, sql string) (*pgconn.StatementDescription, error)
@@ -14,6 +17,7 @@ type Query interface {
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
 
+// Transaction is a Query bound to an open transaction.
 type Transaction interface {
 	Query
 	Commit(ctx context.Context) error
@@ -27,3 +31,9 @@ type db interface {
 type withTx[T any] interface {
 	WithTx(tx Transaction) T
 }
+
+var (
+	_ Query       = (*pgx.Conn)(nil)
+	_ Query       = Transaction(nil)
+	_ Transaction = (pgx.Tx)(nil)
+)
@@ -0,0 +1,75 @@
+package trm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrAckFailed is returned when the message of WithAckOnCommit could not be acknowledged although
+// the transaction committed.
+var ErrAckFailed = errors.New("trm: message acknowledgement failed")
+
+// WithAckOnCommit ties a consumed message to the transaction of a single InTxWith call: ack is
+// called once the transaction committed, nack once the call failed without committing, after
+// retries, including when it panicked or failed before beginning. Each call makes exactly one of
+// them, from the goroutine of InTxWith.
+//
+// A failed ack is returned as ErrAckFailed although the transaction committed: the message will be
+// redelivered, so consumption must be idempotent, as it must be anyway for a commit whose outcome
+// is uncertain, which is nacked. A failed nack is joined to the error of the call.
+func WithAckOnCommit(ack, nack func() error) CallOption {
+	return func(c *call) {
+		c.ack = &messageAck{ack: ack, nack: nack}
+	}
+}
+
+type messageAck struct {
+	ack  func() error
+	nack func() error
+}
+
+// settle acknowledges the message after the call returned st and err.
+func (slf *messageAck) settle(st *txState, err error) error {
+	if st != nil && st.committed {
+		errAck := slf.ack()
+		if errAck != nil {
+			return errors.Join(err, fmt.Errorf("%w: %w", ErrAckFailed, errAck))
+		}
+
+		return err
+	}
+
+	errNack := slf.nack()
+	if errNack != nil {
+		return errors.Join(err, fmt.Errorf("nack message: %w", errNack))
+	}
+
+	return err
+}
+
+// runAcked is runOn that settles the message of WithAckOnCommit, nacking it when runOn panics.
+func (slf *impl[T]) runAcked(
+	ctx context.Context,
+	c *call,
+	fn func(ctx context.Context, repo T) error,
+) (*txState, error) {
+	settled := false
+
+	defer func() {
+		if !settled {
+			_ = c.ack.nack()
+		}
+	}()
+
+	var st *txState
+
+	db, err := slf.resolveDB(ctx)
+	if err == nil {
+		st, err = slf.runOn(ctx, db, c, fn)
+	}
+
+	settled = true
+
+	return st, c.ack.settle(st, err)
+}
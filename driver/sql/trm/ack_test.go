@@ -0,0 +1,117 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type WithAckOnCommit struct {
+	suite.Suite
+
+	ctx   context.Context
+	mock  sqlmock.Sqlmock
+	impl  *trm.Impl[*mockWithTx]
+	calls []string
+}
+
+func (slf *WithAckOnCommit) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{})
+	slf.calls = nil
+}
+
+func (slf *WithAckOnCommit) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *WithAckOnCommit) ackOnCommit(ackErr, nackErr error) trm.CallOption {
+	return trm.WithAckOnCommit(
+		func() error {
+			slf.calls = append(slf.calls, "ack")
+			return ackErr
+		},
+		func() error {
+			slf.calls = append(slf.calls, "nack")
+			return nackErr
+		},
+	)
+}
+
+func (slf *WithAckOnCommit) TestAckOnCommit() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxWith(slf.ctx, func(*mockWithTx) error {
+		slf.Empty(slf.calls)
+		return nil
+	}, slf.ackOnCommit(nil, nil))
+	slf.Require().NoError(err)
+	slf.Equal([]string{"ack"}, slf.calls)
+}
+
+func (slf *WithAckOnCommit) TestNackOnRollback() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	errFn := errors.New("fn")
+	err := slf.impl.InTxWith(slf.ctx, func(*mockWithTx) error { return errFn }, slf.ackOnCommit(nil, nil))
+	slf.Require().ErrorIs(err, errFn)
+	slf.Equal([]string{"nack"}, slf.calls)
+}
+
+func (slf *WithAckOnCommit) TestNackOnCommitFailure() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit().WillReturnError(errors.New("connection reset"))
+
+	err := slf.impl.InTxWith(slf.ctx, func(*mockWithTx) error { return nil }, slf.ackOnCommit(nil, nil))
+	slf.Require().Error(err)
+	slf.Equal([]string{"nack"}, slf.calls)
+}
+
+func (slf *WithAckOnCommit) TestNackOnPanic() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	slf.Panics(func() {
+		_ = slf.impl.InTxWith(slf.ctx, func(*mockWithTx) error { panic("boom") }, slf.ackOnCommit(nil, nil))
+	})
+	slf.Equal([]string{"nack"}, slf.calls)
+}
+
+func (slf *WithAckOnCommit) TestAckFailed() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	errBroker := errors.New("broker unavailable")
+	err := slf.impl.InTxWith(slf.ctx, func(*mockWithTx) error { return nil }, slf.ackOnCommit(errBroker, nil))
+	slf.Require().ErrorIs(err, trm.ErrAckFailed)
+	slf.Require().ErrorIs(err, errBroker)
+	slf.Equal([]string{"ack"}, slf.calls)
+}
+
+func (slf *WithAckOnCommit) TestNackFailed() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	errFn := errors.New("fn")
+	errBroker := errors.New("broker unavailable")
+	err := slf.impl.InTxWith(slf.ctx, func(*mockWithTx) error { return errFn }, slf.ackOnCommit(nil, errBroker))
+	slf.Require().ErrorIs(err, errFn)
+	slf.Require().ErrorIs(err, errBroker)
+	slf.Require().NotErrorIs(err, trm.ErrAckFailed)
+	slf.Equal([]string{"nack"}, slf.calls)
+}
+
+func TestWithAckOnCommit(t *testing.T) {
+	suite.Run(t, new(WithAckOnCommit))
+}
@@ -0,0 +1,60 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+// maxAllocOverhead is the number of allocations InTx of a transactor built with New(db, adapter)
+// and no options may add to a native BeginTx and Commit: the transaction state and the context
+// carrying it.
+const maxAllocOverhead = 2
+
+// nopConnector opens connections whose transactions do nothing, so only allocations of
+// database/sql and the transactor are measured.
+type nopConnector struct{}
+
+func (nopConnector) Connect(context.Context) (driver.Conn, error) { return nopConn{}, nil }
+func (nopConnector) Driver() driver.Driver                        { return nil }
+
+type nopConn struct{}
+
+func (nopConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (nopConn) Close() error                        { return nil }
+func (nopConn) Begin() (driver.Tx, error)           { return nopConn{}, nil }
+func (nopConn) Commit() error                       { return nil }
+func (nopConn) Rollback() error                     { return nil }
+
+type Allocs struct {
+	suite.Suite
+}
+
+func (slf *Allocs) TestDefaultPathOverhead() {
+	db := sql.OpenDB(nopConnector{})
+	defer func() { _ = db.Close() }()
+
+	ctx := context.Background()
+	native := testing.AllocsPerRun(1000, func() {
+		tx, err := db.BeginTx(ctx, nil)
+		slf.Require().NoError(err)
+		slf.Require().NoError(tx.Commit())
+	})
+
+	impl := trm.New(db, &mockWithTx{})
+	fn := func(*mockWithTx) error { return nil }
+	got := testing.AllocsPerRun(1000, func() {
+		slf.Require().NoError(impl.InTx(ctx, fn))
+	})
+
+	slf.LessOrEqual(got-native, float64(maxAllocOverhead), "native %v, InTx %v", native, got)
+}
+
+func TestAllocs(t *testing.T) {
+	suite.Run(t, new(Allocs))
+}
@@ -0,0 +1,188 @@
+package trm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrAsyncHooksDisabled = errors.New("trm: async hooks are disabled, see WithAsyncHooks")
+	ErrClosed             = errors.New("trm: transactor closed")
+	ErrAsyncHookPanicked  = errors.New("trm: async hook panicked")
+)
+
+// WithAsyncHooks enables OnCommitAsync with a pool of workers goroutines owned by the transactor,
+// started on first use, and a queue of up to queue hooks. Hook errors are passed to onError;
+// a panicking hook is reported there as ErrAsyncHookPanicked instead of crashing the process.
+//
+// A full queue blocks the committed InTx until a worker frees a slot, bounding memory when
+// downstream is slow. Call Close on shutdown to wait for the queued hooks.
+func WithAsyncHooks(workers, queue int, onError func(ctx context.Context, err error)) Option {
+	return func(c *config) {
+		c.async = &asyncPool{
+			workers: max(workers, 1),
+			jobs:    make(chan asyncJob, max(queue, 0)),
+			quit:    make(chan struct{}),
+			onError: onError,
+		}
+	}
+}
+
+// OnCommitAsync registers fn to run on the WithAsyncHooks worker pool after the transaction
+// carried by ctx commits, without delaying the return of InTx, e.g. to publish events over
+// the network. fn receives ctx detached from its cancellation. Like OnCommit hooks, async
+// hooks are discarded on rollback.
+func OnCommitAsync(ctx context.Context, fn func(ctx context.Context) error) error {
+	st := stateFrom(ctx)
+	if st == nil || st.committed {
+		return fmt.Errorf("on commit: %w", ErrNoTransaction)
+	}
+
+	if st.cfg.async == nil {
+		return fmt.Errorf("on commit: %w", ErrAsyncHooksDisabled)
+	}
+
+	st.addOnCommit(func(ctx context.Context) error {
+		return st.cfg.async.enqueue(context.WithoutCancel(ctx), fn)
+	})
+
+	return nil
+}
+
+// Drain waits until every async hook queued so far has finished, or until ctx is done.
+func (slf *impl[T]) Drain(ctx context.Context) error {
+	if slf.cfg.async == nil {
+		return nil
+	}
+
+	return slf.cfg.async.drain(ctx)
+}
+
+// Close stops accepting async hooks, failing later OnCommitAsync hooks with ErrClosed,
+// and waits for the queued ones like Drain before stopping the workers.
+// Transactions keep working without async hooks.
+func (slf *impl[T]) Close(ctx context.Context) error {
+	if slf.cfg.async == nil {
+		return nil
+	}
+
+	return slf.cfg.async.close(ctx)
+}
+
+type asyncJob struct {
+	ctx context.Context
+	fn  func(ctx context.Context) error
+}
+
+type asyncPool struct {
+	workers int
+	jobs    chan asyncJob
+	quit    chan struct{}
+	onError func(ctx context.Context, err error)
+	start   sync.Once
+	stop    sync.Once
+
+	mu      sync.Mutex
+	closed  bool
+	pending int
+	// idle is closed once pending drops to zero.
+	idle chan struct{}
+}
+
+func (slf *asyncPool) enqueue(ctx context.Context, fn func(ctx context.Context) error) error {
+	slf.mu.Lock()
+	if slf.closed {
+		slf.mu.Unlock()
+		return ErrClosed
+	}
+
+	slf.pending++
+	if slf.pending == 1 {
+		slf.idle = make(chan struct{})
+	}
+	slf.mu.Unlock()
+
+	slf.start.Do(func() {
+		for range slf.workers {
+			go slf.work()
+		}
+	})
+
+	slf.jobs <- asyncJob{ctx: ctx, fn: fn}
+
+	return nil
+}
+
+func (slf *asyncPool) work() {
+	for {
+		select {
+		case job := <-slf.jobs:
+			err := slf.run(job)
+			if err != nil && slf.onError != nil {
+				slf.onError(job.ctx, err)
+			}
+
+			slf.done()
+		case <-slf.quit:
+			return
+		}
+	}
+}
+
+// run runs the hook of job, turning a panic into an error so the worker survives it.
+func (slf *asyncPool) run(job asyncJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrAsyncHookPanicked, r)
+		}
+	}()
+
+	return job.fn(job.ctx)
+}
+
+func (slf *asyncPool) done() {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	slf.pending--
+	if slf.pending == 0 {
+		close(slf.idle)
+	}
+}
+
+func (slf *asyncPool) drain(ctx context.Context) error {
+	slf.mu.Lock()
+	if slf.pending == 0 {
+		slf.mu.Unlock()
+		return nil
+	}
+
+	idle := slf.idle
+	slf.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (slf *asyncPool) close(ctx context.Context) error {
+	slf.mu.Lock()
+	slf.closed = true
+	slf.mu.Unlock()
+
+	err := slf.drain(ctx)
+	if err != nil {
+		return err
+	}
+
+	slf.stop.Do(func() {
+		close(slf.quit)
+	})
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type AsyncHooks struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+
+	mu     sync.Mutex
+	errs   []error
+	events []string
+}
+
+func (slf *AsyncHooks) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.errs = nil
+	slf.events = nil
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithAsyncHooks(2, 4, func(_ context.Context, err error) {
+		slf.mu.Lock()
+		defer slf.mu.Unlock()
+
+		slf.errs = append(slf.errs, err)
+	}))
+}
+
+func (slf *AsyncHooks) TearDownTest() {
+	slf.NoError(slf.impl.Close(slf.ctx))
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *AsyncHooks) record(event string) {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	slf.events = append(slf.events, event)
+}
+
+func (slf *AsyncHooks) TestDoesNotBlockInTx() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	release := make(chan struct{})
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		return trm.OnCommitAsync(ctx, func(context.Context) error {
+			<-release
+			slf.record("published")
+
+			return errors.New("kafka")
+		})
+	})
+	slf.Require().NoError(err)
+	slf.record("returned")
+
+	close(release)
+	slf.Require().NoError(slf.impl.Drain(slf.ctx))
+	slf.Equal([]string{"returned", "published"}, slf.events)
+	slf.Require().Len(slf.errs, 1)
+	slf.EqualError(slf.errs[0], "kafka")
+}
+
+func (slf *AsyncHooks) TestPanicReported() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		for range 3 {
+			err := trm.OnCommitAsync(ctx, func(context.Context) error {
+				panic("boom")
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	slf.Require().NoError(err)
+
+	slf.Require().NoError(slf.impl.Drain(slf.ctx))
+	slf.Require().Len(slf.errs, 3)
+
+	for _, err := range slf.errs {
+		slf.Require().ErrorIs(err, trm.ErrAsyncHookPanicked)
+		slf.Contains(err.Error(), "boom")
+	}
+}
+
+func (slf *AsyncHooks) TestDrainDeadline() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		return trm.OnCommitAsync(ctx, func(context.Context) error {
+			<-release
+			return nil
+		})
+	})
+	slf.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(slf.ctx)
+	cancel()
+	slf.Require().ErrorIs(slf.impl.Drain(ctx), context.Canceled)
+}
+
+func (slf *AsyncHooks) TestDiscardedOnRollback() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		slf.Require().NoError(trm.OnCommitAsync(ctx, func(context.Context) error {
+			slf.record("published")
+			return nil
+		}))
+
+		return errors.New("err")
+	})
+	slf.Require().Error(err)
+	slf.Require().NoError(slf.impl.Drain(slf.ctx))
+	slf.Empty(slf.events)
+}
+
+func (slf *AsyncHooks) TestClosed() {
+	slf.Require().NoError(slf.impl.Close(slf.ctx))
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		return trm.OnCommitAsync(ctx, func(context.Context) error { return nil })
+	})
+	slf.Require().ErrorIs(err, trm.ErrClosed)
+}
+
+func (slf *AsyncHooks) TestDisabled() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err = trm.New(db, &mockWithTx{}).InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		return trm.OnCommitAsync(ctx, func(context.Context) error { return nil })
+	})
+	slf.Require().ErrorIs(err, trm.ErrAsyncHooksDisabled)
+}
+
+func TestAsyncHooks(t *testing.T) {
+	suite.Run(t, new(AsyncHooks))
+}
@@ -0,0 +1,15 @@
+package trm
+
+import "context"
+
+// Attempt returns the 1-based number of the attempt whose transaction ctx carries, e.g. to log
+// retries or to make a test callback fail only the first time, or 0 outside of a transaction.
+// Begin failures count as attempts, as in InTxAttempts.
+func Attempt(ctx context.Context) int {
+	st := stateFrom(ctx)
+	if st == nil {
+		return 0
+	}
+
+	return st.attempt
+}
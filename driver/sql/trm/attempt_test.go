@@ -0,0 +1,75 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type Attempt struct {
+	suite.Suite
+}
+
+func (slf *Attempt) TestPerAttemptContext() {
+	synctest.Test(slf.T(), func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		defer func() { _ = db.Close() }()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("SET LOCAL idle_in_transaction_session_timeout").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec("SET LOCAL idle_in_transaction_session_timeout").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		impl := trm.New(db, &mockWithTx{},
+			trm.WithMaxTxDuration(time.Second),
+			trm.WithRollbackDecider(func(_ context.Context, attempt int, _ error) bool { return attempt < 2 }),
+		)
+
+		var (
+			attempts  []int
+			deadlines []time.Time
+		)
+
+		outer := context.Background()
+		err = impl.InTxCtx(outer, func(ctx context.Context, _ *mockWithTx) error {
+			attempts = append(attempts, trm.Attempt(ctx))
+			deadline, _ := ctx.Deadline()
+			deadlines = append(deadlines, deadline)
+
+			require.Zero(t, trm.Attempt(outer))
+
+			if trm.Attempt(ctx) == 1 {
+				time.Sleep(100 * time.Millisecond)
+				return errors.New("serialization failure")
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, []int{1, 2}, attempts)
+		require.Len(t, deadlines, 2)
+		require.Equal(t, 100*time.Millisecond, deadlines[1].Sub(deadlines[0]))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func (slf *Attempt) TestOutsideTransaction() {
+	slf.Zero(trm.Attempt(context.Background()))
+}
+
+func TestAttempt(t *testing.T) {
+	suite.Run(t, new(Attempt))
+}
@@ -0,0 +1,144 @@
+package trm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// errBatchPanic rolls back the savepoint of a batched callback that panicked.
+var errBatchPanic = errors.New("trm: batched callback panicked")
+
+// Batcher groups InTx calls arriving within a short window into one transaction (group commit),
+// trading a little latency for far fewer commits under heavy write load, e.g. in an append-only
+// event store. See NewBatcher.
+type Batcher[T any] struct {
+	impl     *impl[T]
+	window   time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	batch *batch[T]
+}
+
+type batch[T any] struct {
+	calls []*batchCall[T]
+	// full is closed once the batch reached maxBatch calls.
+	full chan struct{}
+}
+
+type batchCall[T any] struct {
+	fn   func(repo T) error
+	err  error
+	done chan struct{}
+	// panicked is the value the callback or the transaction panicked with, re-panicked by InTx.
+	panicked any
+}
+
+// run runs the callback of c, turning a panic into an error so its savepoint is rolled back.
+func (slf *batchCall[T]) run(repo T) error {
+	err := errBatchPanic
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				slf.panicked = r
+			}
+		}()
+
+		err = slf.fn(repo)
+	}()
+
+	return err
+}
+
+// NewBatcher returns a Batcher collecting calls for up to window after the first one of
+// a batch, or until maxBatch calls were collected.
+func (slf *impl[T]) NewBatcher(window time.Duration, maxBatch int) *Batcher[T] {
+	return &Batcher[T]{
+		impl:     slf,
+		window:   window,
+		maxBatch: max(maxBatch, 1),
+	}
+}
+
+// InTx runs fn in the transaction of the current batch and returns once the batch is done.
+//
+// Every callback runs in its own savepoint: a failing callback is rolled back alone and only
+// its caller receives the error, while the others still commit. A failed commit is returned to
+// every caller whose callback succeeded. Callbacks of a batch run one after another, so a retried
+// transaction runs all of them again.
+//
+// The transaction runs with the context of the first caller of the batch, detached from its
+// cancellation so it does not fail the others; callers wait for the batch even when their
+// context is done, as their callback may already have run. A panicking callback is rolled back
+// alone like a failing one, and InTx panics with the same value in the goroutine of its caller.
+func (slf *Batcher[T]) InTx(ctx context.Context, fn func(repo T) error) error {
+	c := &batchCall[T]{fn: fn, done: make(chan struct{})}
+
+	slf.mu.Lock()
+	b := slf.batch
+	if b == nil {
+		b = &batch[T]{full: make(chan struct{})}
+		slf.batch = b
+
+		go slf.flush(context.WithoutCancel(ctx), b)
+	}
+
+	b.calls = append(b.calls, c)
+	if len(b.calls) == slf.maxBatch {
+		slf.batch = nil
+		close(b.full)
+	}
+	slf.mu.Unlock()
+
+	<-c.done
+
+	if c.panicked != nil {
+		panic(c.panicked)
+	}
+
+	return c.err
+}
+
+// flush runs b in one transaction once its window elapsed or it is full.
+func (slf *Batcher[T]) flush(ctx context.Context, b *batch[T]) {
+	timer := time.NewTimer(slf.window)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		slf.mu.Lock()
+		if slf.batch == b {
+			slf.batch = nil
+		}
+		slf.mu.Unlock()
+	case <-b.full:
+	}
+
+	var panicked any
+
+	err := func() error {
+		defer func() {
+			panicked = recover()
+		}()
+
+		return slf.impl.InTxCtx(ctx, func(ctx context.Context, _ T) error {
+			for _, c := range b.calls {
+				c.err = NewSavepoint[T](ctx).Run(c.run)
+			}
+
+			return nil
+		})
+	}()
+
+	for _, c := range b.calls {
+		if c.err == nil {
+			c.err = err
+			c.panicked = panicked
+		}
+
+		close(c.done)
+	}
+}
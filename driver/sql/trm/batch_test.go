@@ -0,0 +1,165 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type Batcher struct {
+	suite.Suite
+}
+
+// run starts one InTx call of batcher per callback, in order, and returns their errors.
+func (slf *Batcher) run(batcher *trm.Batcher[*txRepo], fns ...func(r *txRepo) error) []error {
+	errs := make([]error, len(fns))
+	done := make(chan struct{})
+
+	for i, fn := range fns {
+		go func() {
+			errs[i] = batcher.InTx(context.Background(), fn)
+			done <- struct{}{}
+		}()
+
+		// Let the call join the batch before the next one, so the order is deterministic.
+		synctest.Wait()
+	}
+
+	for range fns {
+		<-done
+	}
+
+	return errs
+}
+
+func (slf *Batcher) test(fn func(t *testing.T, mock sqlmock.Sqlmock, batcher *trm.Batcher[*txRepo])) {
+	synctest.Test(slf.T(), func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+
+		defer func() { _ = db.Close() }()
+
+		impl := trm.New(db, &txRepo{}, trm.WithIDGenerator(func() string { return "tx" }))
+
+		fn(t, mock, impl.NewBatcher(10*time.Millisecond, 3))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func insertEvent(item string) func(r *txRepo) error {
+	return func(r *txRepo) error {
+		_, err := r.tx.ExecContext(context.Background(), "INSERT INTO events (item) VALUES ($1)", item)
+		return err
+	}
+}
+
+func (slf *Batcher) expectInsert(mock sqlmock.Sqlmock, sp, item string) {
+	mock.ExpectExec("SAVEPOINT " + sp).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO events (item) VALUES ($1)").WithArgs(item).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("RELEASE SAVEPOINT " + sp).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (slf *Batcher) TestGroupCommit() {
+	slf.test(func(t *testing.T, mock sqlmock.Sqlmock, batcher *trm.Batcher[*txRepo]) {
+		mock.ExpectBegin()
+		slf.expectInsert(mock, "sp_1_tx", "a")
+		slf.expectInsert(mock, "sp_2_tx", "b")
+		mock.ExpectCommit()
+
+		start := time.Now()
+		errs := slf.run(batcher, insertEvent("a"), insertEvent("b"))
+		require.Equal(t, []error{nil, nil}, errs)
+		require.Equal(t, 10*time.Millisecond, time.Since(start))
+	})
+}
+
+func (slf *Batcher) TestFullBatchDoesNotWait() {
+	slf.test(func(t *testing.T, mock sqlmock.Sqlmock, batcher *trm.Batcher[*txRepo]) {
+		mock.ExpectBegin()
+		slf.expectInsert(mock, "sp_1_tx", "a")
+		slf.expectInsert(mock, "sp_2_tx", "b")
+		slf.expectInsert(mock, "sp_3_tx", "c")
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		slf.expectInsert(mock, "sp_1_tx", "d")
+		mock.ExpectCommit()
+
+		start := time.Now()
+		errs := slf.run(batcher, insertEvent("a"), insertEvent("b"), insertEvent("c"))
+		require.Equal(t, []error{nil, nil, nil}, errs)
+		require.Zero(t, time.Since(start))
+
+		errs = slf.run(batcher, insertEvent("d"))
+		require.Equal(t, []error{nil}, errs)
+	})
+}
+
+func (slf *Batcher) TestCallbackErrorIsIsolated() {
+	slf.test(func(t *testing.T, mock sqlmock.Sqlmock, batcher *trm.Batcher[*txRepo]) {
+		errFailed := errors.New("failed")
+
+		mock.ExpectBegin()
+		slf.expectInsert(mock, "sp_1_tx", "a")
+		mock.ExpectExec("SAVEPOINT sp_2_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_2_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+		slf.expectInsert(mock, "sp_3_tx", "c")
+		mock.ExpectCommit()
+
+		errs := slf.run(batcher, insertEvent("a"), func(*txRepo) error { return errFailed }, insertEvent("c"))
+		require.NoError(t, errs[0])
+		require.ErrorIs(t, errs[1], errFailed)
+		require.NoError(t, errs[2])
+	})
+}
+
+func (slf *Batcher) TestCommitErrorReachesEveryCaller() {
+	slf.test(func(t *testing.T, mock sqlmock.Sqlmock, batcher *trm.Batcher[*txRepo]) {
+		errCommit := errors.New("commit failed")
+		errFailed := errors.New("failed")
+
+		mock.ExpectBegin()
+		slf.expectInsert(mock, "sp_1_tx", "a")
+		mock.ExpectExec("SAVEPOINT sp_2_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_2_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit().WillReturnError(errCommit)
+
+		errs := slf.run(batcher, insertEvent("a"), func(*txRepo) error { return errFailed })
+		require.ErrorIs(t, errs[0], errCommit)
+		require.ErrorIs(t, errs[1], errFailed)
+		require.NotErrorIs(t, errs[1], errCommit)
+	})
+}
+
+func (slf *Batcher) TestCallbackPanicIsIsolated() {
+	slf.test(func(t *testing.T, mock sqlmock.Sqlmock, batcher *trm.Batcher[*txRepo]) {
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sp_1_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+		slf.expectInsert(mock, "sp_2_tx", "b")
+		mock.ExpectCommit()
+
+		recovered := make(chan any, 1)
+		go func() {
+			defer func() { recovered <- recover() }()
+
+			_ = batcher.InTx(context.Background(), func(*txRepo) error { panic("boom") })
+		}()
+		synctest.Wait()
+
+		errs := slf.run(batcher, insertEvent("b"))
+		require.Equal(t, []error{nil}, errs)
+		require.Equal(t, "boom", <-recovered)
+	})
+}
+
+func TestBatcher(t *testing.T) {
+	suite.Run(t, new(Batcher))
+}
@@ -0,0 +1,96 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+var errFailover = errors.New("failover")
+
+type OnBeginFailure struct {
+	suite.Suite
+
+	ctx   context.Context
+	mock  sqlmock.Sqlmock
+	impl  *trm.Impl[*mockWithTx]
+	calls []error
+}
+
+func (slf *OnBeginFailure) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.calls = nil
+	slf.impl = trm.New(db, &mockWithTx{}, trm.OnBeginFailure(func(_ context.Context, err error) bool {
+		slf.calls = append(slf.calls, err)
+		return errors.Is(err, errFailover) && len(slf.calls) < 3
+	}))
+}
+
+func (slf *OnBeginFailure) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *OnBeginFailure) TestRetryAfterFailover() {
+	slf.mock.ExpectBegin().WillReturnError(errFailover)
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+	slf.Require().Len(slf.calls, 1)
+
+	var errBegin *trm.BeginError
+	slf.Require().ErrorAs(slf.calls[0], &errBegin)
+	slf.Require().ErrorIs(errBegin, errFailover)
+}
+
+func (slf *OnBeginFailure) TestPermanentError() {
+	slf.mock.ExpectBegin().WillReturnError(errors.New("bad password"))
+
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().EqualError(err, "begin tx: bad password")
+
+	var errBegin *trm.BeginError
+	slf.Require().ErrorAs(err, &errBegin)
+	slf.Len(slf.calls, 1)
+}
+
+func (slf *OnBeginFailure) TestHookBoundsRetries() {
+	for range 3 {
+		slf.mock.ExpectBegin().WillReturnError(errFailover)
+	}
+
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().ErrorIs(err, errFailover)
+	slf.Len(slf.calls, 3)
+}
+
+func (slf *OnBeginFailure) TestCallbackErrorNotHooked() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return errFailover })
+	slf.Require().ErrorIs(err, errFailover)
+	slf.Empty(slf.calls)
+}
+
+func (slf *OnBeginFailure) TestNoRetry() {
+	slf.mock.ExpectBegin().WillReturnError(errFailover)
+
+	err := slf.impl.InTxWith(slf.ctx, func(*mockWithTx) error { return nil }, trm.NoRetry())
+	slf.Require().ErrorIs(err, errFailover)
+	slf.Empty(slf.calls)
+}
+
+func TestOnBeginFailure(t *testing.T) {
+	suite.Run(t, new(OnBeginFailure))
+}
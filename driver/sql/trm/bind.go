@@ -0,0 +1,17 @@
+package trm
+
+// Bind2 binds two repositories to tx, like an adapter's WithTx does by hand, e.g. in the extra
+// function of InTxExtra when a callback needs repositories the shared adapter does not have.
+func Bind2[A withTx[A], B withTx[B]](tx Transaction, a A, b B) (A, B) {
+	return a.WithTx(tx), b.WithTx(tx)
+}
+
+// Bind3 is Bind2 for three repositories.
+func Bind3[A withTx[A], B withTx[B], C withTx[C]](tx Transaction, a A, b B, c C) (A, B, C) {
+	return a.WithTx(tx), b.WithTx(tx), c.WithTx(tx)
+}
+
+// Bind4 is Bind2 for four repositories.
+func Bind4[A withTx[A], B withTx[B], C withTx[C], D withTx[D]](tx Transaction, a A, b B, c C, d D) (A, B, C, D) {
+	return a.WithTx(tx), b.WithTx(tx), c.WithTx(tx), d.WithTx(tx)
+}
@@ -0,0 +1,65 @@
+package trm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type otherRepo struct {
+	tx trm.Transaction
+}
+
+func (r *otherRepo) WithTx(tx trm.Transaction) *otherRepo {
+	return &otherRepo{tx: tx}
+}
+
+type Bind struct {
+	suite.Suite
+
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+}
+
+func (slf *Bind) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{})
+}
+
+func (slf *Bind) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *Bind) TestBindsToOneTransaction() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	var bound []trm.Transaction
+
+	err := slf.impl.InTxExtra(context.Background(), func(tx trm.Transaction) {
+		a2, b2 := trm.Bind2(tx, &txRepo{}, &otherRepo{})
+		a3, b3, c3 := trm.Bind3(tx, &txRepo{}, &otherRepo{}, &txRepo{})
+		a4, b4, c4, d4 := trm.Bind4(tx, &txRepo{}, &otherRepo{}, &txRepo{}, &otherRepo{})
+
+		bound = []trm.Transaction{tx, a2.tx, b2.tx, a3.tx, b3.tx, c3.tx, a4.tx, b4.tx, c4.tx, d4.tx}
+	}, func(*mockWithTx) error {
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Require().Len(bound, 10)
+
+	for _, tx := range bound[1:] {
+		slf.Same(bound[0], tx)
+	}
+}
+
+func TestBind(t *testing.T) {
+	suite.Run(t, new(Bind))
+}
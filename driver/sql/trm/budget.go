@@ -0,0 +1,34 @@
+package trm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrBudgetExhausted = errors.New("trm: time budget exhausted")
+
+// WithBudget returns a copy of ctx whose deadline is total from now, shared by every InTx call made
+// with it, e.g. the transactions of one request honoring its SLA together: each call runs with what
+// is left of the budget instead of a fresh WithDefaultTimeout, and once the budget ran out InTx fails
+// with ErrBudgetExhausted, also matching context.DeadlineExceeded, before beginning.
+//
+// A nested budget can only shorten the deadline. Resources of the budget are released once it runs
+// out or ctx is done.
+func WithBudget(ctx context.Context, total time.Duration) context.Context {
+	ctx, cancel := context.WithTimeoutCause(ctx, total, ErrBudgetExhausted)
+	// Nothing ends the budget earlier than its deadline or the parent, which both release it.
+	context.AfterFunc(ctx, cancel)
+
+	return ctx
+}
+
+// checkBudget fails an InTx call whose WithBudget budget ran out.
+func checkBudget(ctx context.Context) error {
+	if ctx.Err() == nil || !errors.Is(context.Cause(ctx), ErrBudgetExhausted) {
+		return nil
+	}
+
+	return fmt.Errorf("begin tx: %w: %w", ErrBudgetExhausted, ctx.Err())
+}
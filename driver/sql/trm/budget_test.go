@@ -0,0 +1,84 @@
+package trm_test
+
+import (
+	"context"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type Budget struct {
+	suite.Suite
+}
+
+func (slf *Budget) run(fn func(t *testing.T, mock sqlmock.Sqlmock, impl *trm.Impl[*mockWithTx])) {
+	synctest.Test(slf.T(), func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		defer func() { _ = db.Close() }()
+
+		fn(t, mock, trm.New(db, &mockWithTx{}, trm.WithDefaultTimeout(time.Second)))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func (slf *Budget) TestSharedDeadline() {
+	slf.run(func(t *testing.T, mock sqlmock.Sqlmock, impl *trm.Impl[*mockWithTx]) {
+		ctx := trm.WithBudget(context.Background(), 100*time.Millisecond)
+		budgetDeadline, _ := ctx.Deadline()
+
+		for range 2 {
+			mock.ExpectBegin()
+			mock.ExpectCommit()
+
+			err := impl.InTxCtx(ctx, func(ctx context.Context, _ *mockWithTx) error {
+				deadline, ok := ctx.Deadline()
+				require.True(t, ok)
+				require.Equal(t, budgetDeadline, deadline)
+
+				time.Sleep(30 * time.Millisecond)
+
+				return nil
+			})
+			require.NoError(t, err)
+		}
+	})
+}
+
+func (slf *Budget) TestExhausted() {
+	slf.run(func(t *testing.T, _ sqlmock.Sqlmock, impl *trm.Impl[*mockWithTx]) {
+		ctx := trm.WithBudget(context.Background(), 100*time.Millisecond)
+		// Past the deadline, so the timer of the budget has fired whatever the order of equal timers.
+		time.Sleep(101 * time.Millisecond)
+
+		err := impl.InTx(ctx, func(*mockWithTx) error {
+			require.Fail(t, "callback must not run")
+			return nil
+		})
+		require.ErrorIs(t, err, trm.ErrBudgetExhausted)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func (slf *Budget) TestParentCanceled() {
+	slf.run(func(t *testing.T, _ sqlmock.Sqlmock, impl *trm.Impl[*mockWithTx]) {
+		parent, cancel := context.WithCancel(context.Background())
+		ctx := trm.WithBudget(parent, time.Second)
+		cancel()
+
+		err := impl.InTx(ctx, func(*mockWithTx) error { return nil })
+		require.ErrorIs(t, err, context.Canceled)
+		require.NotErrorIs(t, err, trm.ErrBudgetExhausted)
+	})
+}
+
+func TestBudget(t *testing.T) {
+	suite.Run(t, new(Budget))
+}
@@ -0,0 +1,96 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// CallOption configures a single InTxWith call.
+type CallOption func(*call)
+
+type call struct {
+	txOpts  *sql.TxOptions
+	noRetry bool
+	timeout time.Duration
+	intent  intent
+	// lockTables are locked in order by InTxLocking.
+	lockTables []string
+	// cleanups collects the Cleanup functions of InTxWithCleanup.
+	cleanups *cleanupList
+	// guard rejects writes, see ReadOnlyGuard.
+	guard bool
+	// ack settles the consumed message of WithAckOnCommit.
+	ack *messageAck
+	// attempts is set by runOn to the number of attempts made.
+	attempts int
+}
+
+func newCall(opts []CallOption) *call {
+	c := &call{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+type txOptionsKey struct{}
+
+// WithTxOptions sets the default options every transaction is started with.
+func WithTxOptions(opts *sql.TxOptions) Option {
+	return func(c *config) {
+		c.txOpts = opts
+	}
+}
+
+// WithContextOptions returns a context whose transactions are started with opts,
+// e.g. to let an HTTP middleware mark all transactions of a GET request read-only.
+// It overrides WithTxOptions and is overridden by the TxOptions call option.
+func WithContextOptions(ctx context.Context, opts *sql.TxOptions) context.Context {
+	return context.WithValue(ctx, txOptionsKey{}, opts)
+}
+
+// TxOptions starts the transaction of a single InTxWith call with opts.
+func TxOptions(opts *sql.TxOptions) CallOption {
+	return func(c *call) {
+		c.txOpts = opts
+	}
+}
+
+// NoRetry runs the callback of a single InTxWith call at most once: no failure is retried,
+// whatever WithRollbackDecider and OnBeginFailure decide, e.g. for callbacks with side effects
+// that cannot be repeated.
+func NoRetry() CallOption {
+	return func(c *call) {
+		c.noRetry = true
+	}
+}
+
+func (slf *config) txOptions(ctx context.Context, c *call) *sql.TxOptions {
+	opts := slf.resolveTxOptions(ctx, c)
+	if c == nil || c.intent == intentNone {
+		return opts
+	}
+
+	withIntent := sql.TxOptions{}
+	if opts != nil {
+		withIntent = *opts
+	}
+
+	withIntent.ReadOnly = c.intent == intentRead
+
+	return &withIntent
+}
+
+func (slf *config) resolveTxOptions(ctx context.Context, c *call) *sql.TxOptions {
+	if c != nil && c.txOpts != nil {
+		return c.txOpts
+	}
+
+	if opts, ok := ctx.Value(txOptionsKey{}).(*sql.TxOptions); ok && opts != nil {
+		return opts
+	}
+
+	return slf.txOpts
+}
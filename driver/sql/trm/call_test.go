@@ -0,0 +1,82 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type TxOptions struct {
+	suite.Suite
+
+	ctx     context.Context
+	mock    sqlmock.Sqlmock
+	impl    *trm.Impl[*mockWithTx]
+	newOpts *sql.TxOptions
+}
+
+func (slf *TxOptions) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.newOpts = &sql.TxOptions{Isolation: sql.LevelRepeatableRead}
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithTxOptions(slf.newOpts))
+}
+
+func (slf *TxOptions) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *TxOptions) TestNewLevel() {
+	slf.Same(slf.newOpts, trm.TxOptionsOf(slf.impl, slf.ctx))
+}
+
+func (slf *TxOptions) TestContextOverridesNew() {
+	ctxOpts := &sql.TxOptions{ReadOnly: true}
+
+	ctx := trm.WithContextOptions(slf.ctx, ctxOpts)
+	slf.Same(ctxOpts, trm.TxOptionsOf(slf.impl, ctx))
+}
+
+func (slf *TxOptions) TestCallOverridesContext() {
+	callOpts := &sql.TxOptions{Isolation: sql.LevelSerializable}
+
+	ctx := trm.WithContextOptions(slf.ctx, &sql.TxOptions{ReadOnly: true})
+	slf.Same(callOpts, trm.TxOptionsOf(slf.impl, ctx, trm.TxOptions(callOpts)))
+}
+
+func (slf *TxOptions) TestBeginEventReportsOptions() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+	slf.mock = mock
+
+	var begins []trm.Event
+	impl := trm.New(db, &mockWithTx{}, trm.WithEventSink(func(_ context.Context, e trm.Event) {
+		if e.Kind == trm.EventBegin {
+			begins = append(begins, e)
+		}
+	}))
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	err = impl.InTxWith(
+		slf.ctx,
+		func(*mockWithTx) error { return nil },
+		trm.TxOptions(&sql.TxOptions{Isolation: sql.LevelSerializable}),
+	)
+	slf.Require().NoError(err)
+	slf.Require().Len(begins, 1)
+	slf.Equal(sql.LevelSerializable, begins[0].TxOptions.Isolation)
+}
+
+func TestTxOptions(t *testing.T) {
+	suite.Run(t, new(TxOptions))
+}
@@ -0,0 +1,70 @@
+package trm
+
+import (
+	"context"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Packages whose frames are skipped looking for the call site of a transaction.
+const (
+	trmPackage = "github.com/metalfm/transactor/driver/sql/trm"
+	trPackage  = "github.com/metalfm/transactor/tr"
+)
+
+type callerKey struct{}
+
+// WithCallerInfo records the source location that started every transaction, see Caller.
+// It is also reported in Event.Caller, e.g. as a metrics label: file:line is bounded by the code
+// base, so its cardinality is bounded too. Capturing it costs a stack walk per InTx.
+func WithCallerInfo() Option {
+	return func(c *config) {
+		c.callerInfo = true
+	}
+}
+
+// Caller returns the location that started the transaction carried by ctx as dir/file.go:line,
+// the first frame outside this package and package tr. It is empty without WithCallerInfo.
+func Caller(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
+
+// withCaller records in ctx the location that started the transaction.
+func (slf *config) withCaller(ctx context.Context) context.Context {
+	if !slf.callerInfo {
+		return ctx
+	}
+
+	var pcs [32]uintptr
+
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	for {
+		f, more := frames.Next()
+
+		switch funcPackage(f.Function) {
+		case trmPackage, trPackage:
+		default:
+			return context.WithValue(ctx, callerKey{}, path.Base(path.Dir(f.File))+"/"+path.Base(f.File)+
+				":"+strconv.Itoa(f.Line))
+		}
+
+		if !more {
+			return ctx
+		}
+	}
+}
+
+// funcPackage returns the import path of the package a function name from runtime.Frame belongs to.
+func funcPackage(name string) string {
+	name, _, _ = strings.Cut(name, "[")
+	slash := strings.LastIndexByte(name, '/')
+	dot := strings.IndexByte(name[slash+1:], '.')
+	if dot < 0 {
+		return name
+	}
+
+	return name[:slash+1+dot]
+}
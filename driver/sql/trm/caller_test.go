@@ -0,0 +1,71 @@
+package trm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+	"github.com/metalfm/transactor/tr"
+)
+
+type CallerInfo struct {
+	suite.Suite
+
+	mock   sqlmock.Sqlmock
+	impl   *trm.Impl[*mockWithTx]
+	events []trm.Event
+}
+
+func (slf *CallerInfo) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+	slf.events = nil
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithCallerInfo(), trm.WithEventSink(func(_ context.Context, e trm.Event) {
+		slf.events = append(slf.events, e)
+	}))
+}
+
+func (slf *CallerInfo) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *CallerInfo) TestCallSite() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	var caller string
+	err := slf.impl.InTxCtx(context.Background(), func(ctx context.Context, _ *mockWithTx) error {
+		caller = trm.Caller(ctx)
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Regexp(`^trm/caller_test\.go:\d+$`, caller)
+	slf.Require().Len(slf.events, 2)
+	slf.Equal(caller, slf.events[0].Caller)
+	slf.Equal(caller, slf.events[1].Caller)
+}
+
+func (slf *CallerInfo) TestSkipsDecorators() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	var base tr.Transactor[*mockWithTx] = slf.impl
+	err := tr.Adapt(base, func(r *mockWithTx) *mockWithTx { return r }).
+		InTx(context.Background(), func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+	slf.Require().Len(slf.events, 2)
+	slf.Regexp(`^trm/caller_test\.go:\d+$`, slf.events[0].Caller)
+}
+
+func (slf *CallerInfo) TestDisabled() {
+	slf.Empty(trm.Caller(context.Background()))
+}
+
+func TestCallerInfo(t *testing.T) {
+	suite.Run(t, new(CallerInfo))
+}
@@ -0,0 +1,127 @@
+package trm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+const (
+	ClassNone                = ""
+	ClassCanceled            = "canceled"
+	ClassDeadlineExceeded    = "deadline_exceeded"
+	ClassUniqueViolation     = "unique_violation"
+	ClassForeignKeyViolation = "foreign_key_violation"
+	ClassNotNullViolation    = "not_null_violation"
+	ClassCheckViolation      = "check_violation"
+	ClassIntegrityViolation  = "integrity_constraint_violation"
+	ClassSerialization       = "serialization_failure"
+	ClassDeadlock            = "deadlock_detected"
+	ClassLockNotAvailable    = "lock_not_available"
+	ClassQueryCanceled       = "query_canceled"
+	ClassReadOnly            = "read_only_transaction"
+	ClassConnection          = "connection_exception"
+	ClassOther               = "other"
+)
+
+// WithErrorClassifier replaces ClassifySQLState as the function that fills Event.Class
+// of rollback events, e.g. to be used as a metrics label. Keep the number of classes bounded.
+func WithErrorClassifier(classify func(err error) string) Option {
+	return func(c *config) {
+		c.classify = classify
+	}
+}
+
+// ClassifySQLState maps err to a bounded set of failure classes based on its SQLSTATE.
+//
+// It understands lib/pq and pgx errors (SQLState() string method) and go-sql-driver/mysql
+// errors (SQLState [5]byte field) without depending on the drivers. Unknown SQLSTATEs are
+// reported as ClassOther, errors without one as ClassOther as well, and nil as ClassNone.
+func ClassifySQLState(err error) string {
+	switch {
+	case err == nil:
+		return ClassNone
+	case errors.Is(err, context.Canceled):
+		return ClassCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ClassDeadlineExceeded
+	}
+
+	code := SQLState(err)
+	switch code {
+	case "23505":
+		return ClassUniqueViolation
+	case "23503":
+		return ClassForeignKeyViolation
+	case "23502":
+		return ClassNotNullViolation
+	case "23514":
+		return ClassCheckViolation
+	case "40001":
+		return ClassSerialization
+	case "40P01":
+		return ClassDeadlock
+	case "55P03":
+		return ClassLockNotAvailable
+	case "57014":
+		return ClassQueryCanceled
+	case "25006":
+		return ClassReadOnly
+	}
+
+	if len(code) == 5 {
+		switch code[:2] {
+		case "23":
+			return ClassIntegrityViolation
+		case "08":
+			return ClassConnection
+		}
+	}
+
+	return ClassOther
+}
+
+type sqlStater interface {
+	SQLState() string
+}
+
+// SQLState returns the SQLSTATE code carried by err or any error it wraps, or "" if there is none.
+func SQLState(err error) string {
+	var s sqlStater
+	if errors.As(err, &s) {
+		return s.SQLState()
+	}
+
+	for err != nil {
+		if code := sqlStateField(err); code != "" {
+			return code
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return ""
+}
+
+func sqlStateField(err error) string {
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+
+	f := v.FieldByName("SQLState")
+	if !f.IsValid() || f.Kind() != reflect.Array || f.Len() != 5 || f.Type().Elem().Kind() != reflect.Uint8 {
+		return ""
+	}
+
+	b := make([]byte, 5)
+	for i := range b {
+		b[i] = byte(f.Index(i).Uint())
+	}
+
+	return string(b)
+}
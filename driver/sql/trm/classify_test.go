@@ -0,0 +1,117 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type pgError struct {
+	code string
+}
+
+func (e *pgError) Error() string    { return "pg: " + e.code }
+func (e *pgError) SQLState() string { return e.code }
+
+type MySQLError struct {
+	Number   uint16
+	SQLState [5]byte
+}
+
+func (e *MySQLError) Error() string { return "mysql" }
+
+type ClassifyError struct {
+	suite.Suite
+}
+
+func (slf *ClassifyError) TestClassifySQLState() {
+	cases := map[string]error{
+		trm.ClassNone:                nil,
+		trm.ClassCanceled:            fmt.Errorf("wrap: %w", context.Canceled),
+		trm.ClassDeadlineExceeded:    context.DeadlineExceeded,
+		trm.ClassUniqueViolation:     fmt.Errorf("wrap: %w", &pgError{code: "23505"}),
+		trm.ClassForeignKeyViolation: &pgError{code: "23503"},
+		trm.ClassSerialization:       &pgError{code: "40001"},
+		trm.ClassDeadlock:            &pgError{code: "40P01"},
+		trm.ClassReadOnly:            &pgError{code: "25006"},
+		trm.ClassIntegrityViolation:  fmt.Errorf("wrap: %w", &MySQLError{Number: 1062, SQLState: [5]byte{'2', '3', '0', '0', '0'}}),
+		trm.ClassConnection:          &pgError{code: "08006"},
+		trm.ClassOther:               errors.New("plain"),
+	}
+
+	for expected, err := range cases {
+		slf.Equal(expected, trm.ClassifySQLState(err), "%v", err)
+	}
+}
+
+func (slf *ClassifyError) TestRollbackEventClass() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	var events []trm.Event
+	impl := trm.New(db, &mockWithTx{}, trm.WithEventSink(func(_ context.Context, e trm.Event) {
+		events = append(events, e)
+	}))
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err = impl.InTx(context.Background(), func(*mockWithTx) error {
+		return &pgError{code: "23505"}
+	})
+	slf.Require().Error(err)
+	slf.Require().Len(events, 2)
+	slf.Equal(trm.ClassUniqueViolation, events[1].Class)
+	slf.NoError(mock.ExpectationsWereMet())
+}
+
+func (slf *ClassifyError) TestCustomClassifier() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	var events []trm.Event
+	impl := trm.New(db, &mockWithTx{},
+		trm.WithEventSink(func(_ context.Context, e trm.Event) { events = append(events, e) }),
+		trm.WithErrorClassifier(func(error) string { return "custom" }),
+	)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err = impl.InTx(context.Background(), func(*mockWithTx) error { return errors.New("err") })
+	slf.Require().Error(err)
+	slf.Equal("custom", events[1].Class)
+}
+
+func (slf *ClassifyError) TestReadOnlyTransaction() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	impl := trm.New(db, &mockWithTx{})
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	readOnly := &pgError{code: "25006"}
+	err = impl.InTx(context.Background(), func(*mockWithTx) error { return readOnly })
+	slf.Require().ErrorIs(err, trm.ErrReadOnlyTransaction)
+	slf.Require().ErrorIs(err, readOnly)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err = impl.InTx(context.Background(), func(*mockWithTx) error { return &pgError{code: "23505"} })
+	slf.Require().Error(err)
+	slf.NotErrorIs(err, trm.ErrReadOnlyTransaction)
+	slf.NoError(mock.ExpectationsWereMet())
+}
+
+func TestClassifyError(t *testing.T) {
+	suite.Run(t, new(ClassifyError))
+}
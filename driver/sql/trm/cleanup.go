@@ -0,0 +1,75 @@
+package trm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNoCleanup is returned by Cleanup outside a callback of InTxWithCleanup.
+var ErrNoCleanup = errors.New("trm: cleanup registered outside InTxWithCleanup")
+
+// InTxWithCleanup is InTxCtx that also returns a function releasing the resources the callback
+// registered with Cleanup, e.g. session-level advisory locks or temporary tables that post-commit
+// work still uses. The transactor never runs cleanup functions itself: the caller must call the
+// returned function once done, whether InTx failed or not, typically with defer.
+//
+// Cleanup functions registered by every attempt are kept, as a failed attempt may have acquired
+// resources outside the rolled back transaction too. The returned function runs them once, in
+// LIFO order, and returns their errors joined; later calls return nil. It is never nil.
+func (slf *impl[T]) InTxWithCleanup(
+	ctx context.Context,
+	fn func(ctx context.Context, repo T) error,
+) (func() error, error) {
+	c := &call{cleanups: &cleanupList{}}
+	_, err := slf.run(ctx, c, fn)
+
+	return c.cleanups.run, err
+}
+
+// Cleanup registers fn to run when the caller of InTxWithCleanup invokes the function it returned,
+// not at commit. It returns ErrNoCleanup when ctx does not carry a transaction of InTxWithCleanup.
+func Cleanup(ctx context.Context, fn func() error) error {
+	st := stateFrom(ctx)
+	if st == nil || st.cleanups == nil {
+		return fmt.Errorf("cleanup: %w", ErrNoCleanup)
+	}
+
+	st.cleanups.add(fn)
+
+	return nil
+}
+
+type cleanupList struct {
+	mu   sync.Mutex
+	fns  []func() error
+	once sync.Once
+}
+
+func (slf *cleanupList) add(fn func() error) {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	slf.fns = append(slf.fns, fn)
+}
+
+func (slf *cleanupList) run() error {
+	var err error
+
+	slf.once.Do(func() {
+		slf.mu.Lock()
+		fns := slf.fns
+		slf.fns = nil
+		slf.mu.Unlock()
+
+		errs := make([]error, 0, len(fns))
+		for i := len(fns) - 1; i >= 0; i-- {
+			errs = append(errs, fns[i]())
+		}
+
+		err = errors.Join(errs...)
+	})
+
+	return err
+}
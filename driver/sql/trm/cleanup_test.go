@@ -0,0 +1,103 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type Cleanup struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+}
+
+func (slf *Cleanup) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithRollbackDecider(func(_ context.Context, attempt int, _ error) bool {
+		return attempt < 2
+	}))
+}
+
+func (slf *Cleanup) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *Cleanup) TestRunsOnDemandInLIFOOrder() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	var order []string
+	cleanup, err := slf.impl.InTxWithCleanup(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		slf.Require().NoError(trm.Cleanup(ctx, func() error {
+			order = append(order, "lock")
+			return nil
+		}))
+
+		return trm.Cleanup(ctx, func() error {
+			order = append(order, "temp table")
+			return nil
+		})
+	})
+	slf.Require().NoError(err)
+	slf.Empty(order)
+
+	slf.Require().NoError(cleanup())
+	slf.Equal([]string{"temp table", "lock"}, order)
+
+	slf.Require().NoError(cleanup())
+	slf.Len(order, 2)
+}
+
+func (slf *Cleanup) TestEveryAttemptAndErrorsJoined() {
+	errRetry := errors.New("retry")
+	errRelease := errors.New("release")
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	attempts := 0
+	cleanup, err := slf.impl.InTxWithCleanup(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		attempts++
+		slf.Require().NoError(trm.Cleanup(ctx, func() error { return errRelease }))
+
+		return errRetry
+	})
+	slf.Require().ErrorIs(err, errRetry)
+	slf.Equal(2, attempts)
+
+	err = cleanup()
+	slf.Require().ErrorIs(err, errRelease)
+	slf.Len(err.(interface{ Unwrap() []error }).Unwrap(), 2)
+}
+
+func (slf *Cleanup) TestOutsideInTxWithCleanup() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		return trm.Cleanup(ctx, func() error { return nil })
+	})
+	slf.Require().ErrorIs(err, trm.ErrNoCleanup)
+
+	slf.Require().ErrorIs(trm.Cleanup(slf.ctx, func() error { return nil }), trm.ErrNoCleanup)
+}
+
+func TestCleanup(t *testing.T) {
+	suite.Run(t, new(Cleanup))
+}
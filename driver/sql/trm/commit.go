@@ -0,0 +1,100 @@
+package trm
+
+import (
+	"context"
+	"fmt"
+)
+
+// WithCommitContext decouples the fate of a decided transaction from the operation context.
+//
+// database/sql binds a transaction to the context it was begun with and rolls it back as soon as
+// that context is done. Without this option a client disconnecting after the callback returned nil
+// but before COMMIT reached the server aborts the commit, and the caller cannot tell whether work it
+// already considered done was persisted.
+//
+// With this option the transaction is begun with a context detached from ctx, so only the commit
+// is bounded, by the context fn derives from ctx. fn returns the cancel function of that context
+// and InTx defers it, releasing the context as soon as the commit returned, e.g.:
+//
+//	trm.WithCommitContext(func(parent context.Context) (context.Context, context.CancelFunc) {
+//		return context.WithTimeout(context.WithoutCancel(parent), 5*time.Second)
+//	})
+//
+// which InTx runs around the commit as:
+//
+//	ctx, cancel := fn(parent)
+//	defer cancel()
+//
+// Statements in the callback still run with the operation context, so a cancelled request still
+// fails them and the transaction rolls back as usual; once the callback returned nil the commit
+// is attempted regardless of ctx. Beginning the transaction is not cancelled with ctx either.
+// Durability is still only guaranteed by a nil error: when fn's context expires during COMMIT the
+// outcome remains unknown, exactly as with a network failure.
+func WithCommitContext(fn func(parent context.Context) (context.Context, context.CancelFunc)) Option {
+	return func(c *config) {
+		c.commitCtx = fn
+	}
+}
+
+// WithBeforeCommit runs hook synchronously right before every commit, after the callback and
+// DeferInTx functions succeeded; an error rolls the transaction back. It is a test hook: blocking
+// on a channel in it pauses a transaction at a precise point to interleave concurrent transactions,
+// e.g. to provoke serialization failures and deadlocks without sleeps.
+func WithBeforeCommit(hook func(ctx context.Context) error) Option {
+	return func(c *config) {
+		c.beforeCommit = hook
+	}
+}
+
+// beginContext returns the context a transaction is begun with and the function that
+// releases it. With WithCommitContext it is detached from ctx.
+func (slf *config) beginContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if slf.commitCtx == nil {
+		return ctx, func() {}
+	}
+
+	return context.WithCancel(context.WithoutCancel(ctx))
+}
+
+// prepareCommit runs what has to succeed in the transaction of st right before its commit.
+func (slf *config) prepareCommit(ctx context.Context, st *txState) error {
+	err := st.runDeferred()
+	if err != nil {
+		return fmt.Errorf("defer in tx: %w", err)
+	}
+
+	if slf.beforeCommit != nil {
+		err = slf.beforeCommit(ctx)
+		if err != nil {
+			return fmt.Errorf("before commit: %w", err)
+		}
+	}
+
+	if st.rollbackOnly.Load() {
+		return ErrRollbackOnly
+	}
+
+	if slf.maxRowsAffected > 0 {
+		return writeSetError(st.writeSet.Load(), slf.maxRowsAffected)
+	}
+
+	return nil
+}
+
+// commit commits st, rolling it back through cancelTx once the commit context is done.
+func (slf *config) commit(ctx context.Context, st *txState, cancelTx context.CancelFunc) error {
+	err := slf.fault(FaultCommit)
+	if err != nil {
+		return err
+	}
+
+	if slf.commitCtx != nil {
+		commitCtx, cancel := slf.commitCtx(ctx)
+		defer cancel()
+
+		stop := context.AfterFunc(commitCtx, cancelTx)
+		defer stop()
+	}
+
+	return st.txn.Commit()
+}
@@ -0,0 +1,139 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type CommitContext struct {
+	suite.Suite
+
+	mock      sqlmock.Sqlmock
+	impl      *trm.Impl[*mockWithTx]
+	parents   []context.Context
+	commitCtx context.Context
+	cancel    context.CancelFunc
+}
+
+func (slf *CommitContext) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+	slf.parents = nil
+	slf.commitCtx = nil
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithCommitContext(
+		func(parent context.Context) (context.Context, context.CancelFunc) {
+			slf.parents = append(slf.parents, parent)
+			slf.commitCtx, slf.cancel = context.WithCancel(context.WithoutCancel(parent))
+
+			return slf.commitCtx, slf.cancel
+		},
+	))
+}
+
+func (slf *CommitContext) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *CommitContext) TestCommitSurvivesCancelledRequest() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := slf.impl.InTx(ctx, func(*mockWithTx) error {
+		cancel()
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Len(slf.parents, 1)
+	slf.Require().ErrorIs(slf.commitCtx.Err(), context.Canceled, "commit context released")
+}
+
+func (slf *CommitContext) TestCallbackErrorRollsBack() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	expected := errors.New("callback")
+	err := slf.impl.InTx(context.Background(), func(*mockWithTx) error {
+		return expected
+	})
+	slf.Require().ErrorIs(err, expected)
+	slf.Empty(slf.parents)
+}
+
+func TestCommitContext(t *testing.T) {
+	suite.Run(t, new(CommitContext))
+}
+
+type BeforeCommit struct {
+	suite.Suite
+
+	mock    sqlmock.Sqlmock
+	hookErr error
+	order   []string
+	impl    *trm.Impl[*mockWithTx]
+}
+
+func (slf *BeforeCommit) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+	slf.hookErr = nil
+	slf.order = nil
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithBeforeCommit(func(ctx context.Context) error {
+		slf.NotEmpty(trm.TxID(ctx))
+		slf.order = append(slf.order, "before commit")
+
+		return slf.hookErr
+	}))
+}
+
+func (slf *BeforeCommit) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *BeforeCommit) TestRunsBeforeCommit() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(context.Background(), func(ctx context.Context, _ *mockWithTx) error {
+		slf.order = append(slf.order, "callback")
+
+		return trm.OnCommit(ctx, func(context.Context) error {
+			slf.order = append(slf.order, "on commit")
+			return nil
+		})
+	})
+	slf.Require().NoError(err)
+	slf.Equal([]string{"callback", "before commit", "on commit"}, slf.order)
+}
+
+func (slf *BeforeCommit) TestErrorRollsBack() {
+	slf.hookErr = errors.New("err")
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(context.Background(), func(*mockWithTx) error { return nil })
+	slf.Require().EqualError(err, "before commit: err")
+}
+
+func (slf *BeforeCommit) TestNotRunAfterCallbackError() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(context.Background(), func(*mockWithTx) error { return errors.New("err") })
+	slf.Require().Error(err)
+	slf.Empty(slf.order)
+}
+
+func TestBeforeCommit(t *testing.T) {
+	suite.Run(t, new(BeforeCommit))
+}
@@ -0,0 +1,128 @@
+package trm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Composer derives the WithTx method of a composite adapter, a struct of repositories, so it
+// cannot forget a repository added later:
+//
+//	func NewAdapter(db *sql.DB) *Adapter {
+//		adapter := &Adapter{Orders: NewOrders(db), Users: NewUsers(db), Q: db}
+//		adapter.composer = trm.NewComposer(adapter)
+//
+//		return adapter
+//	}
+//
+//	func (slf *Adapter) WithTx(tx trm.Transaction) *Adapter {
+//		return slf.composer.WithTx(tx)
+//	}
+type Composer[S any] struct {
+	adapter *S
+	fields  []composedField
+}
+
+// composedField is a field bound by a Composer: query fields hold the transaction itself,
+// the others the result of their method number method, WithTx.
+type composedField struct {
+	index  int
+	query  bool
+	method int
+}
+
+// NewComposer returns the Composer of adapter. The fields of adapter are resolved once, here:
+// a field is bound if it is an interface every Transaction implements, e.g. Query, which then
+// holds the transaction itself, or if it has a WithTx method accepting a Transaction and
+// returning a value of the field type, which is then stored in the field. Other fields, e.g.
+// a logger or the Composer itself, are copied as is.
+//
+// NewComposer panics when adapter is nil, when a field to bind is unexported or nil, when a
+// WithTx method cannot bind its field, or when there is no field to bind: all are programming
+// errors, reported when the adapter is built rather than by the first transaction.
+func NewComposer[S any](adapter *S) *Composer[S] {
+	if adapter == nil {
+		panic(fmt.Sprintf("trm: NewComposer needs a non-nil %T", adapter))
+	}
+
+	v := reflect.ValueOf(adapter).Elem()
+	if v.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("trm: NewComposer needs a pointer to a struct, got %T", adapter))
+	}
+
+	c := &Composer[S]{adapter: adapter}
+	self := reflect.TypeFor[*Composer[S]]()
+
+	for i := range v.NumField() {
+		if v.Field(i).Type() == self {
+			continue
+		}
+
+		field, ok := composeField(v, i)
+		if ok {
+			c.fields = append(c.fields, field)
+		}
+	}
+
+	if len(c.fields) == 0 {
+		panic(fmt.Sprintf("trm: NewComposer found no field of %T to bind to the transaction", adapter))
+	}
+
+	return c
+}
+
+// composeField resolves the field i of v, reporting whether it is bound.
+func composeField(v reflect.Value, i int) (composedField, bool) {
+	f, sf := v.Field(i), v.Type().Field(i)
+	name := v.Type().String() + "." + sf.Name
+	txType := reflect.TypeFor[Transaction]()
+
+	query := f.Kind() == reflect.Interface && f.NumMethod() > 0 && txType.Implements(f.Type())
+
+	m, ok := f.Type().MethodByName("WithTx")
+	if !query && !ok {
+		return composedField{}, false
+	}
+
+	if !sf.IsExported() {
+		panic(fmt.Sprintf("trm: NewComposer cannot bind the unexported field %s", name))
+	}
+
+	if query {
+		return composedField{index: i, query: true}, true
+	}
+
+	if (f.Kind() == reflect.Pointer || f.Kind() == reflect.Interface) && f.IsNil() {
+		panic(fmt.Sprintf("trm: NewComposer cannot bind the nil field %s", name))
+	}
+
+	t := f.Method(m.Index).Type()
+	if t.NumIn() != 1 || t.NumOut() != 1 || !txType.AssignableTo(t.In(0)) || !t.Out(0).AssignableTo(f.Type()) {
+		panic(fmt.Sprintf("trm: NewComposer cannot bind %s: its WithTx is %s", name, t))
+	}
+
+	return composedField{index: i, method: m.Index}, true
+}
+
+// WithTx returns a copy of the adapter with every bound field bound to tx. Nothing is looked up:
+// the WithTx methods of the fields resolved by NewComposer are only called.
+func (slf *Composer[S]) WithTx(tx Transaction) *S {
+	composed := new(S)
+	*composed = *slf.adapter
+
+	v := reflect.ValueOf(composed).Elem()
+	txValue := reflect.ValueOf(tx)
+	args := []reflect.Value{txValue}
+
+	for _, field := range slf.fields {
+		f := v.Field(field.index)
+		if field.query {
+			f.Set(txValue)
+			continue
+		}
+
+		f.Set(f.Method(field.method).Call(args)[0])
+	}
+
+	return composed
+}
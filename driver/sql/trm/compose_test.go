@@ -0,0 +1,118 @@
+package trm_test
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type userRepo interface {
+	WithTx(tx trm.Transaction) userRepo
+}
+
+type userRepoImpl struct {
+	tx trm.Transaction
+}
+
+func (r *userRepoImpl) WithTx(tx trm.Transaction) userRepo {
+	return &userRepoImpl{tx: tx}
+}
+
+// badRepo cannot be bound: its WithTx returns another type.
+type badRepo struct{}
+
+func (r *badRepo) WithTx(trm.Transaction) *otherRepo {
+	return &otherRepo{}
+}
+
+type composite struct {
+	Orders *txRepo
+	Other  *otherRepo
+	Users  userRepo
+	Q      trm.Query
+	name   string
+
+	composer *trm.Composer[composite]
+}
+
+func (slf *composite) WithTx(tx trm.Transaction) *composite {
+	return slf.composer.WithTx(tx)
+}
+
+type Compose struct {
+	suite.Suite
+
+	tx trm.Transaction
+}
+
+func (slf *Compose) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	mock.ExpectBegin()
+	slf.tx, err = db.Begin()
+	slf.Require().NoError(err)
+}
+
+func (slf *Compose) TestBindsEveryField() {
+	db, _, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	adapter := &composite{
+		Orders: &txRepo{},
+		Other:  &otherRepo{},
+		Users:  &userRepoImpl{},
+		Q:      db,
+		name:   "adapter",
+	}
+	adapter.composer = trm.NewComposer(adapter)
+
+	bound := adapter.WithTx(slf.tx)
+	slf.NotSame(adapter, bound)
+	slf.Same(slf.tx, bound.Orders.tx)
+	slf.Same(slf.tx, bound.Other.tx)
+	slf.Same(slf.tx, bound.Users.(*userRepoImpl).tx)
+	slf.Same(slf.tx, bound.Q)
+	slf.Equal("adapter", bound.name)
+	slf.Same(adapter.composer, bound.composer)
+
+	slf.Nil(adapter.Orders.tx)
+	slf.Same(db, adapter.Q)
+}
+
+func (slf *Compose) TestNilAdapter() {
+	slf.Panics(func() {
+		trm.NewComposer((*composite)(nil))
+	})
+}
+
+func (slf *Compose) TestNilField() {
+	slf.PanicsWithValue("trm: NewComposer cannot bind the nil field trm_test.composite.Other", func() {
+		trm.NewComposer(&composite{Orders: &txRepo{}, Users: &userRepoImpl{}})
+	})
+}
+
+func (slf *Compose) TestUnbindableWithTx() {
+	slf.Panics(func() {
+		trm.NewComposer(&struct{ Bad *badRepo }{Bad: &badRepo{}})
+	})
+}
+
+func (slf *Compose) TestUnexportedField() {
+	slf.Panics(func() {
+		trm.NewComposer(&struct{ orders *txRepo }{orders: &txRepo{}})
+	})
+}
+
+func (slf *Compose) TestNothingToBind() {
+	slf.Panics(func() {
+		trm.NewComposer(&struct{ name string }{})
+	})
+}
+
+func TestCompose(t *testing.T) {
+	suite.Run(t, new(Compose))
+}
@@ -0,0 +1,42 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDeferredConstraint is matched by errors.Is when the commit failed with an integrity constraint
+// violation (SQLSTATE class 23): a deferred constraint, see WithDeferredConstraints, was checked
+// at commit and failed. ClassifySQLState still reports the precise class, e.g. foreign_key_violation.
+var ErrDeferredConstraint = errors.New("trm: deferred constraint violated at commit")
+
+// WithDeferredConstraints issues SET CONSTRAINTS ALL DEFERRED right after begin, so constraints
+// declared DEFERRABLE are checked at commit instead of after every statement, allowing intermediate
+// states that violate them, e.g. when inserting a graph or a hierarchy in any order. Constraints
+// not declared DEFERRABLE are still checked immediately. If it cannot be set, the transaction is
+// rolled back; a commit failing on a deferred constraint matches ErrDeferredConstraint.
+func WithDeferredConstraints() Option {
+	return func(c *config) {
+		c.setup = append(c.setup, func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, "SET CONSTRAINTS ALL DEFERRED")
+			if err != nil {
+				return fmt.Errorf("defer constraints: %w", err)
+			}
+
+			return nil
+		})
+	}
+}
+
+// markDeferredConstraint makes a commit error match ErrDeferredConstraint when the database
+// rejected the commit because of an integrity constraint.
+func markDeferredConstraint(err error) error {
+	if !strings.HasPrefix(SQLState(err), "23") {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", ErrDeferredConstraint, err)
+}
@@ -0,0 +1,77 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type DeferredConstraints struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+}
+
+func (slf *DeferredConstraints) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithDeferredConstraints())
+}
+
+func (slf *DeferredConstraints) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *DeferredConstraints) TestDeferred() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SET CONSTRAINTS ALL DEFERRED").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func (slf *DeferredConstraints) TestViolatedAtCommit() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SET CONSTRAINTS ALL DEFERRED").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit().WillReturnError(&pgError{code: "23503"})
+
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().ErrorIs(err, trm.ErrDeferredConstraint)
+	slf.Equal(trm.ClassForeignKeyViolation, trm.ClassifySQLState(err))
+}
+
+func (slf *DeferredConstraints) TestOtherCommitFailure() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SET CONSTRAINTS ALL DEFERRED").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit().WillReturnError(&pgError{code: "40001"})
+
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().Error(err)
+	slf.Require().NotErrorIs(err, trm.ErrDeferredConstraint)
+}
+
+func (slf *DeferredConstraints) TestSetFails() {
+	errDenied := errors.New("denied")
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SET CONSTRAINTS ALL DEFERRED").WillReturnError(errDenied)
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().ErrorIs(err, errDenied)
+}
+
+func TestDeferredConstraints(t *testing.T) {
+	suite.Run(t, new(DeferredConstraints))
+}
@@ -0,0 +1,77 @@
+package trm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CopyFrom bulk loads rows into table using the PostgreSQL COPY protocol inside tx,
+// so the load is atomic with the rest of the transaction. It returns the number of copied rows.
+// table may be qualified with its schema, e.g. billing.orders.
+//
+// It relies on the lib/pq COPY FROM STDIN support (the statement built here is the one pq.CopyIn returns)
+// and does not work with drivers that do not implement it.
+func CopyFrom(ctx context.Context, tx Transaction, table string, columns []string, rows [][]any) (int64, error) {
+	stmt, err := tx.PrepareContext(ctx, copyInStatement(table, columns))
+	if err != nil {
+		return 0, fmt.Errorf("prepare copy: %w", err)
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	for i, row := range rows {
+		_, err = stmt.ExecContext(ctx, row...)
+		if err != nil {
+			return 0, fmt.Errorf("copy row %d: %w", i, err)
+		}
+	}
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("flush copy: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("copy rows affected: %w", err)
+	}
+
+	return n, nil
+}
+
+func copyInStatement(table string, columns []string) string {
+	var b strings.Builder
+
+	b.WriteString("COPY ")
+	b.WriteString(quoteTable(table))
+	b.WriteString(" (")
+	for i, col := range columns {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(quoteIdentifier(col))
+	}
+	b.WriteString(") FROM STDIN")
+
+	return b.String()
+}
+
+// quoteTable quotes every dot-separated part of a possibly schema-qualified table name.
+func quoteTable(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quoteIdentifier(part)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+func quoteIdentifier(name string) string {
+	if end := strings.IndexByte(name, 0); end > -1 {
+		name = name[:end]
+	}
+
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,98 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type CopyFrom struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *CopyFrom) SetupTest() {
+	var (
+		db  *sql.DB
+		err error
+	)
+	db, slf.mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.impl = trm.New(db, &txRepo{})
+}
+
+func (slf *CopyFrom) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *CopyFrom) TestSuccess() {
+	slf.mock.ExpectBegin()
+	prep := slf.mock.ExpectPrepare(`COPY "orders" ("item", "qty") FROM STDIN`)
+	prep.ExpectExec().WithArgs("a", 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	prep.ExpectExec().WithArgs("b", 2).WillReturnResult(sqlmock.NewResult(0, 0))
+	prep.ExpectExec().WithoutArgs().WillReturnResult(sqlmock.NewResult(0, 2))
+	slf.mock.ExpectCommit()
+
+	var n int64
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		var err error
+		n, err = trm.CopyFrom(slf.ctx, r.tx, "orders", []string{"item", "qty"}, [][]any{{"a", 1}, {"b", 2}})
+		return err
+	})
+	slf.Require().NoError(err)
+	slf.Equal(int64(2), n)
+}
+
+func (slf *CopyFrom) TestQuotesIdentifiers() {
+	slf.mock.ExpectBegin()
+	prep := slf.mock.ExpectPrepare(`COPY "weird""table" ("col") FROM STDIN`)
+	prep.ExpectExec().WithoutArgs().WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		_, err := trm.CopyFrom(slf.ctx, r.tx, `weird"table`, []string{"col"}, nil)
+		return err
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *CopyFrom) TestSchemaQualifiedTable() {
+	slf.mock.ExpectBegin()
+	prep := slf.mock.ExpectPrepare(`COPY "billing"."orders" ("item") FROM STDIN`)
+	prep.ExpectExec().WithoutArgs().WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		_, err := trm.CopyFrom(slf.ctx, r.tx, "billing.orders", []string{"item"}, nil)
+		return err
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *CopyFrom) TestRowError() {
+	slf.mock.ExpectBegin()
+	prep := slf.mock.ExpectPrepare(`COPY "orders" ("item") FROM STDIN`)
+	prep.ExpectExec().WithArgs("a").WillReturnError(errors.New("err"))
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		_, err := trm.CopyFrom(slf.ctx, r.tx, "orders", []string{"item"}, [][]any{{"a"}})
+		return err
+	})
+	slf.Require().EqualError(err, "trm callback: copy row 0: err")
+}
+
+func TestCopyFrom(t *testing.T) {
+	suite.Run(t, new(CopyFrom))
+}
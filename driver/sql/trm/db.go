@@ -3,8 +3,13 @@ package trm
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"reflect"
 )
 
+// Query is the set of methods repositories use to execute statements.
+// It is implemented both by the database handle and by Transaction,
+// so a repository stores a single Query field and WithTx replaces it with the transaction.
 type Query interface {
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
 	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
@@ -12,12 +17,53 @@ type Query interface {
 	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
 
+// Transaction is a Query bound to an open transaction.
 type Transaction interface {
 	Query
 	Commit() error
 	Rollback() error
 }
 
+// beginner is where transactions begin: the database or a connection pinned by WithSession.
+type beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// SameQuery returns ErrQueryMismatch unless all qs are the same handle, e.g. to check at
+// construction that the repositories composed into an adapter were built from one *sql.DB.
+// Otherwise WithTx would still bind them all to the transaction, while statements run through
+// the repositories outside a transaction would silently go to different databases.
+// Handles of a type that is not comparable, e.g. a struct holding a slice, are compared with
+// reflect.DeepEqual instead of ==, which would panic.
+func SameQuery(qs ...Query) error {
+	for i, q := range qs {
+		if !sameQuery(q, qs[0]) {
+			return fmt.Errorf("%w: query %d differs from query 0", ErrQueryMismatch, i)
+		}
+	}
+
+	return nil
+}
+
+func sameQuery(a, b Query) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.IsValid() && vb.IsValid() && va.Type() == vb.Type() && !va.Comparable() {
+		return reflect.DeepEqual(a, b)
+	}
+
+	return a == b
+}
+
 type withTx[T any] interface {
 	WithTx(tx Transaction) T
 }
+
+var (
+	_ Query       = (*sql.DB)(nil)
+	_ Query       = Transaction(nil)
+	_ Transaction = (*sql.Tx)(nil)
+	_ Query       = (*sql.Conn)(nil)
+	_ beginner    = (*sql.DB)(nil)
+	_ beginner    = (*sql.Conn)(nil)
+)
@@ -0,0 +1,64 @@
+package trm_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type SameQuery struct {
+	suite.Suite
+}
+
+func (slf *SameQuery) TestSame() {
+	db, _, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.Require().NoError(trm.SameQuery(db, db, db))
+	slf.Require().NoError(trm.SameQuery())
+}
+
+func (slf *SameQuery) TestMismatch() {
+	db1, _, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	db2, _, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	err = trm.SameQuery(db1, db1, db2)
+	slf.Require().ErrorIs(err, trm.ErrQueryMismatch)
+	slf.Require().ErrorContains(err, "query 2 differs")
+}
+
+func (slf *SameQuery) TestNotComparable() {
+	db1, _, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	db2, _, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	q := multiQuery{dbs: []*sql.DB{db1}}
+	slf.Require().NoError(trm.SameQuery(q, q))
+
+	err = trm.SameQuery(q, db1, multiQuery{dbs: []*sql.DB{db2}})
+	slf.Require().ErrorIs(err, trm.ErrQueryMismatch)
+	slf.Require().ErrorContains(err, "query 1 differs")
+
+	err = trm.SameQuery(q, multiQuery{dbs: []*sql.DB{db2}})
+	slf.Require().ErrorContains(err, "query 1 differs")
+}
+
+func TestSameQuery(t *testing.T) {
+	suite.Run(t, new(SameQuery))
+}
+
+// multiQuery is a Query whose type is not comparable.
+type multiQuery struct {
+	trm.Query
+
+	dbs []*sql.DB
+}
@@ -0,0 +1,35 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// WithDeferrable issues SET TRANSACTION DEFERRABLE right after begin in read-only serializable
+// transactions, which sql.TxOptions cannot express. PostgreSQL then waits, when beginning the
+// first statement, for a snapshot that cannot take part in a serialization anomaly, so the
+// transaction never fails with SQLSTATE 40001: the pattern recommended for long-running reports.
+// Other transactions are left as is. If it cannot be set, the transaction is rolled back.
+//
+// PostgreSQL accepts it only before the first query of the transaction, so it is issued before
+// every other setup option, e.g. advisory locks, whatever the order options are passed to New.
+func WithDeferrable() Option {
+	return func(c *config) {
+		c.setup = append([]func(ctx context.Context, tx *sql.Tx) error{setDeferrable}, c.setup...)
+	}
+}
+
+func setDeferrable(ctx context.Context, tx *sql.Tx) error {
+	st := stateFrom(ctx)
+	if st == nil || st.opts == nil || !st.opts.ReadOnly || st.opts.Isolation != sql.LevelSerializable {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, "SET TRANSACTION DEFERRABLE")
+	if err != nil {
+		return fmt.Errorf("set deferrable: %w", err)
+	}
+
+	return nil
+}
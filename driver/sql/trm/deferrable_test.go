@@ -0,0 +1,97 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type Deferrable struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+}
+
+func (slf *Deferrable) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithDeferrable())
+}
+
+func (slf *Deferrable) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *Deferrable) TestReadOnlySerializable() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SET TRANSACTION DEFERRABLE").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxWith(slf.ctx, func(*mockWithTx) error { return nil },
+		trm.TxOptions(&sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}))
+	slf.Require().NoError(err)
+}
+
+func (slf *Deferrable) TestBeforeOtherSetup() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+	impl := trm.New(db, &mockWithTx{},
+		trm.WithSessionVars(func(context.Context) map[string]string { return map[string]string{"app.user": "1"} }),
+		trm.WithStartTimeFromDB(),
+		trm.WithDeferrable(),
+	)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SET TRANSACTION DEFERRABLE").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SELECT set_config($1, $2, true)").WithArgs("app.user", "1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT now()").WillReturnRows(sqlmock.NewRows([]string{"now"}).AddRow(time.Now()))
+	mock.ExpectCommit()
+
+	err = impl.InTxWith(slf.ctx, func(*mockWithTx) error { return nil },
+		trm.TxOptions(&sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}))
+	slf.Require().NoError(err)
+}
+
+func (slf *Deferrable) TestOtherTransactions() {
+	for _, opts := range []*sql.TxOptions{
+		nil,
+		{Isolation: sql.LevelSerializable},
+		{Isolation: sql.LevelRepeatableRead, ReadOnly: true},
+	} {
+		slf.mock.ExpectBegin()
+		slf.mock.ExpectCommit()
+
+		err := slf.impl.InTxWith(slf.ctx, func(*mockWithTx) error { return nil }, trm.TxOptions(opts))
+		slf.Require().NoError(err)
+	}
+}
+
+func (slf *Deferrable) TestFailureRollsBack() {
+	errSet := errors.New("SET TRANSACTION ISOLATION LEVEL must be called before any query")
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SET TRANSACTION DEFERRABLE").WillReturnError(errSet)
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTxWith(slf.ctx, func(*mockWithTx) error { return nil },
+		trm.TxOptions(&sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}))
+	slf.Require().ErrorIs(err, errSet)
+}
+
+func TestDeferrable(t *testing.T) {
+	suite.Run(t, new(Deferrable))
+}
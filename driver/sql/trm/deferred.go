@@ -0,0 +1,63 @@
+package trm
+
+import (
+	"context"
+	"fmt"
+)
+
+// DeferInTx registers fn to run in the transaction carried by ctx after the callback returns nil
+// and before commit, like a transaction-scoped defer: functions run in LIFO order with the repository
+// bound to the transaction, and the first error rolls the transaction back.
+// T must be the repository type of the transactor that started the transaction.
+func DeferInTx[T any](ctx context.Context, fn func(repo T) error) error {
+	st := stateFrom(ctx)
+	if st == nil || st.committed {
+		return fmt.Errorf("defer in tx: %w", ErrNoTransaction)
+	}
+
+	bound := st.binder.bind(st.txn)
+	repo, ok := bound.(T)
+	if !ok {
+		return fmt.Errorf("defer in tx: transactor repository is %T, not %T", bound, repo)
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.deferred = append(st.deferred, func() error {
+		return fn(repo)
+	})
+
+	return nil
+}
+
+// runDeferred runs the functions registered with DeferInTx, including those they register themselves.
+func (slf *txState) runDeferred() error {
+	for {
+		fn := slf.popDeferred()
+		if fn == nil {
+			return nil
+		}
+
+		err := fn()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// popDeferred removes and returns the last function registered with DeferInTx, nil if none is left.
+func (slf *txState) popDeferred() func() error {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	if len(slf.deferred) == 0 {
+		return nil
+	}
+
+	last := len(slf.deferred) - 1
+	fn := slf.deferred[last]
+	slf.deferred = slf.deferred[:last]
+
+	return fn
+}
@@ -0,0 +1,103 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type DeferInTx struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *DeferInTx) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &txRepo{})
+}
+
+func (slf *DeferInTx) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *DeferInTx) TestLIFOBeforeCommit() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("UPDATE b").WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectExec("UPDATE a").WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		for _, table := range []string{"a", "b"} {
+			err := trm.DeferInTx(ctx, func(r *txRepo) error {
+				_, err := r.tx.ExecContext(ctx, "UPDATE "+table+" SET n = 1")
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *DeferInTx) TestFailureRollsBack() {
+	expected := errors.New("deferred")
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		return trm.DeferInTx(ctx, func(*txRepo) error { return expected })
+	})
+	slf.Require().ErrorIs(err, expected)
+}
+
+func (slf *DeferInTx) TestSkippedOnCallbackError() {
+	expected := errors.New("callback")
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	called := false
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		slf.Require().NoError(trm.DeferInTx(ctx, func(*txRepo) error {
+			called = true
+			return nil
+		}))
+
+		return expected
+	})
+	slf.Require().ErrorIs(err, expected)
+	slf.False(called)
+}
+
+func (slf *DeferInTx) TestWrongRepositoryType() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		return trm.DeferInTx(ctx, func(*mockWithTx) error { return nil })
+	})
+	slf.Require().ErrorContains(err, "transactor repository is *trm_test.txRepo, not *trm_test.mockWithTx")
+}
+
+func (slf *DeferInTx) TestOutsideTransaction() {
+	err := trm.DeferInTx(slf.ctx, func(*txRepo) error { return nil })
+	slf.Require().ErrorIs(err, trm.ErrNoTransaction)
+}
+
+func TestDeferInTx(t *testing.T) {
+	suite.Run(t, new(DeferInTx))
+}
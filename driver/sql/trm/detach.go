@@ -0,0 +1,65 @@
+package trm
+
+import (
+	"context"
+	"sync"
+)
+
+// DetachContext returns a context carrying the transaction of ctx, for helpers such as OnCommit,
+// Savepoint or RawTx, but not its cancellation, e.g. for goroutines of an errgroup spawned in an
+// InTxCtx callback whose own context is cancelled by the first failing goroutine. The returned
+// context is cancelled instead once the attempt of the transaction is over, so goroutines outliving
+// it stop rather than using a finished transaction. Outside a transaction it is context.WithoutCancel.
+//
+// WARNING: a transaction is a single connection. database/sql serializes the statements of
+// concurrent goroutines on it, so they gain no parallelism on the database, and a statement
+// started while another goroutine still reads *sql.Rows of the same transaction fails or blocks,
+// depending on the driver. Close rows before starting the next statement, keep Savepoint calls
+// on one goroutine, and wait for every goroutine before the callback returns: statements made
+// after it returned are not part of the commit. OnCommit, OnCommitAsync and DeferInTx may be
+// called from any of the goroutines.
+func DetachContext(ctx context.Context) context.Context {
+	detached := context.WithoutCancel(ctx)
+
+	st := stateFrom(ctx)
+	if st == nil {
+		return detached
+	}
+
+	detached, cancel := context.WithCancel(detached)
+	st.detached.add(cancel)
+
+	return detached
+}
+
+// detachedContexts holds the cancel functions of the contexts returned by DetachContext.
+type detachedContexts struct {
+	mu      sync.Mutex
+	cancels []context.CancelFunc
+	ended   bool
+}
+
+func (slf *detachedContexts) add(cancel context.CancelFunc) {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	if slf.ended {
+		cancel()
+		return
+	}
+
+	slf.cancels = append(slf.cancels, cancel)
+}
+
+// end cancels the detached contexts once the attempt is over.
+func (slf *detachedContexts) end() {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	slf.ended = true
+	for _, cancel := range slf.cancels {
+		cancel()
+	}
+
+	slf.cancels = nil
+}
@@ -0,0 +1,106 @@
+package trm_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type DetachContext struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+}
+
+func (slf *DetachContext) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{})
+}
+
+func (slf *DetachContext) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *DetachContext) TestCarriesTransactionNotCancellation() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("UPDATE accounts").WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectCommit()
+
+	var detached context.Context
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		groupCtx, cancel := context.WithCancel(ctx)
+		detached = trm.DetachContext(groupCtx)
+		cancel()
+
+		slf.Require().NoError(detached.Err())
+
+		tx, ok := trm.RawTx(detached)
+		slf.Require().True(ok)
+
+		_, err := tx.ExecContext(detached, "UPDATE accounts SET balance = 0")
+
+		return err
+	})
+	slf.Require().NoError(err)
+	slf.Require().ErrorIs(detached.Err(), context.Canceled)
+}
+
+func (slf *DetachContext) TestConcurrentHooks() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	var (
+		hooks    atomic.Int32
+		deferred atomic.Int32
+	)
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Go(func() {
+				detached := trm.DetachContext(ctx)
+				slf.NoError(trm.OnCommit(detached, func(context.Context) error {
+					hooks.Add(1)
+					return nil
+				}))
+				slf.NoError(trm.DeferInTx(detached, func(*mockWithTx) error {
+					deferred.Add(1)
+					return nil
+				}))
+			})
+		}
+		wg.Wait()
+
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal(int32(8), hooks.Load())
+	slf.Equal(int32(8), deferred.Load())
+}
+
+func (slf *DetachContext) TestOutsideTransaction() {
+	ctx, cancel := context.WithCancel(slf.ctx)
+	detached := trm.DetachContext(ctx)
+	cancel()
+
+	slf.Require().NoError(detached.Err())
+
+	_, ok := trm.RawTx(detached)
+	slf.False(ok)
+}
+
+func TestDetachContext(t *testing.T) {
+	suite.Run(t, new(DetachContext))
+}
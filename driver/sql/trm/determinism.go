@@ -0,0 +1,114 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNondeterministic is reported by WithDeterminismCheck when the two runs of a callback differed.
+var ErrNondeterministic = errors.New("trm: callback is not deterministic")
+
+// errDryRun rolls back the first run of a callback checked by WithDeterminismCheck.
+var errDryRun = errors.New("dry run")
+
+// WithDeterminismCheck runs every callback twice, to flag callbacks that are unsafe to retry
+// before a retry in production applies them twice: first in a dry run that is always rolled
+// back, then for real. When the runs executed different statements (query and arguments),
+// or only one of them failed, warn is called with an error matching ErrNondeterministic.
+//
+// It is meant for development and tests only: every transaction is run twice, and so are
+// side effects of the callback outside the database, which makes e.g. an email sent from
+// the callback show up twice. The dry run is left out of Stats and events, and skipped for
+// NoRetry calls and with WithExternalTx, whose callbacks are not to be run twice.
+func WithDeterminismCheck(warn func(ctx context.Context, err error)) Option {
+	return func(c *config) {
+		c.determinism = warn
+		c.wrappers = append(c.wrappers, func(st *txState, tx Transaction) Transaction {
+			st.statements = &statementLog{}
+			return &statementLogTx{Transaction: tx, log: st.statements}
+		})
+	}
+}
+
+// dryRuns reports whether the callback of c is run in a dry run before its first attempt.
+func (slf *config) dryRuns(c *call) bool {
+	return slf.determinism != nil && (c == nil || !c.noRetry) && slf.externalTx == nil
+}
+
+// checkDeterminism compares the dry run of a callback with its real run, either of which may be nil.
+func (slf *config) checkDeterminism(ctx context.Context, dry, st *txState, dryErr, err error) {
+	if dry == nil || st == nil || dry.statements == nil || st.statements == nil {
+		return
+	}
+
+	if errors.Is(dryErr, errDryRun) {
+		dryErr = nil
+	}
+
+	if (dryErr != nil) != (err != nil) {
+		slf.determinism(ctx, fmt.Errorf("%w: dry run error %v, real run error %v", ErrNondeterministic, dryErr, err))
+		return
+	}
+
+	dryStmts, stmts := dry.statements.list(), st.statements.list()
+	for i := range max(len(dryStmts), len(stmts)) {
+		var a, b string
+		if i < len(dryStmts) {
+			a = dryStmts[i]
+		}
+
+		if i < len(stmts) {
+			b = stmts[i]
+		}
+
+		if a != b {
+			slf.determinism(ctx, fmt.Errorf("%w: statement %d is %q in the dry run, %q in the real run",
+				ErrNondeterministic, i+1, a, b))
+
+			return
+		}
+	}
+}
+
+type statementLog struct {
+	mu    sync.Mutex
+	stmts []string
+}
+
+func (slf *statementLog) record(query string, args []any) {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	slf.stmts = append(slf.stmts, fmt.Sprintf("%s %v", query, args))
+}
+
+func (slf *statementLog) list() []string {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	return slf.stmts
+}
+
+type statementLogTx struct {
+	Transaction
+
+	log *statementLog
+}
+
+func (slf *statementLogTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	slf.log.record(query, args)
+	return slf.Transaction.ExecContext(ctx, query, args...)
+}
+
+func (slf *statementLogTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	slf.log.record(query, args)
+	return slf.Transaction.QueryContext(ctx, query, args...)
+}
+
+func (slf *statementLogTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	slf.log.record(query, args)
+	return slf.Transaction.QueryRowContext(ctx, query, args...)
+}
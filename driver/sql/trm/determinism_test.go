@@ -0,0 +1,148 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type DeterminismCheck struct {
+	suite.Suite
+
+	ctx      context.Context
+	mock     sqlmock.Sqlmock
+	impl     *trm.Impl[*txRepo]
+	warnings []error
+	events   []trm.EventKind
+}
+
+func (slf *DeterminismCheck) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.warnings = nil
+	slf.events = nil
+	slf.impl = trm.New(db, &txRepo{},
+		trm.WithDeterminismCheck(func(_ context.Context, err error) {
+			slf.warnings = append(slf.warnings, err)
+		}),
+		trm.WithEventSink(func(_ context.Context, e trm.Event) {
+			slf.events = append(slf.events, e.Kind)
+		}),
+	)
+}
+
+func (slf *DeterminismCheck) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *DeterminismCheck) expectInsert(id int) {
+	slf.mock.ExpectExec("INSERT INTO orders (id) VALUES ($1)").WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func (slf *DeterminismCheck) TestDeterministic() {
+	slf.mock.ExpectBegin()
+	slf.expectInsert(1)
+	slf.mock.ExpectRollback()
+	slf.mock.ExpectBegin()
+	slf.expectInsert(1)
+	slf.mock.ExpectCommit()
+
+	runs := 0
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		runs++
+		_, err := r.tx.ExecContext(slf.ctx, "INSERT INTO orders (id) VALUES ($1)", 1)
+		return err
+	})
+	slf.Require().NoError(err)
+	slf.Equal(2, runs)
+	slf.Empty(slf.warnings)
+	slf.Equal([]trm.EventKind{trm.EventBegin, trm.EventCommit}, slf.events)
+	slf.Equal(trm.TxStats{Commits: 1}, slf.impl.Stats())
+}
+
+func (slf *DeterminismCheck) TestNoRetrySkipsDryRun() {
+	slf.mock.ExpectBegin()
+	slf.expectInsert(1)
+	slf.mock.ExpectCommit()
+
+	runs := 0
+	err := slf.impl.InTxWith(slf.ctx, func(r *txRepo) error {
+		runs++
+		_, err := r.tx.ExecContext(slf.ctx, "INSERT INTO orders (id) VALUES ($1)", 1)
+		return err
+	}, trm.NoRetry())
+	slf.Require().NoError(err)
+	slf.Equal(1, runs)
+}
+
+func (slf *DeterminismCheck) TestDifferentArgs() {
+	slf.mock.ExpectBegin()
+	slf.expectInsert(1)
+	slf.mock.ExpectRollback()
+	slf.mock.ExpectBegin()
+	slf.expectInsert(2)
+	slf.mock.ExpectCommit()
+
+	id := 0
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		id++
+		_, err := r.tx.ExecContext(slf.ctx, "INSERT INTO orders (id) VALUES ($1)", id)
+		return err
+	})
+	slf.Require().NoError(err)
+	slf.Require().Len(slf.warnings, 1)
+	slf.Require().ErrorIs(slf.warnings[0], trm.ErrNondeterministic)
+	slf.Require().ErrorContains(slf.warnings[0], "statement 1")
+}
+
+func (slf *DeterminismCheck) TestOnlyOneRunFails() {
+	errFailed := errors.New("failed")
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	runs := 0
+	err := slf.impl.InTx(slf.ctx, func(*txRepo) error {
+		runs++
+		if runs == 2 {
+			return errFailed
+		}
+
+		return nil
+	})
+	slf.Require().ErrorIs(err, errFailed)
+	slf.Require().Len(slf.warnings, 1)
+	slf.Require().ErrorIs(slf.warnings[0], trm.ErrNondeterministic)
+}
+
+func (slf *DeterminismCheck) TestDryRunHooksDiscarded() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	hooks := 0
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		return trm.OnCommit(ctx, func(context.Context) error {
+			hooks++
+			return nil
+		})
+	})
+	slf.Require().NoError(err)
+	slf.Equal(1, hooks)
+	slf.Empty(slf.warnings)
+}
+
+func TestDeterminismCheck(t *testing.T) {
+	suite.Run(t, new(DeterminismCheck))
+}
@@ -0,0 +1,31 @@
+package trm
+
+import "context"
+
+// WithErrorMapper passes every error InTx is about to return to mapper and returns its result
+// instead, e.g. to translate a unique violation into a domain error in one place:
+//
+//	trm.WithErrorMapper(func(_ context.Context, err error) error {
+//		if trm.SQLState(err) == "23505" {
+//			return fmt.Errorf("%w: %w", ErrDuplicate, err)
+//		}
+//		return err
+//	})
+//
+// It sees callback, begin and commit errors of the final attempt, after retries were decided
+// on the original error. Wrapping rather than replacing err keeps ReplayFrom and errors.Is
+// working on the original.
+func WithErrorMapper(mapper func(ctx context.Context, err error) error) Option {
+	return func(c *config) {
+		c.mapError = mapper
+	}
+}
+
+// mapErr returns the error InTx returns for err.
+func (slf *config) mapErr(ctx context.Context, err error) error {
+	if err == nil || slf.mapError == nil {
+		return err
+	}
+
+	return slf.mapError(ctx, err)
+}
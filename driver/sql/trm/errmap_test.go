@@ -0,0 +1,88 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+var errDuplicate = errors.New("duplicate")
+
+type ErrorMapper struct {
+	suite.Suite
+
+	mock   sqlmock.Sqlmock
+	impl   *trm.Impl[*mockWithTx]
+	mapped int
+}
+
+func (slf *ErrorMapper) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+	slf.mapped = 0
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithErrorMapper(func(_ context.Context, err error) error {
+		slf.mapped++
+		if trm.SQLState(err) == "23505" {
+			return fmt.Errorf("%w: %w", errDuplicate, err)
+		}
+
+		return err
+	}))
+}
+
+func (slf *ErrorMapper) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *ErrorMapper) TestCallbackError() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	errUnique := &pgError{code: "23505"}
+	err := slf.impl.InTx(context.Background(), func(*mockWithTx) error {
+		return errUnique
+	})
+	slf.Require().ErrorIs(err, errDuplicate)
+	slf.Require().ErrorIs(err, errUnique)
+	slf.Equal(1, slf.mapped)
+}
+
+func (slf *ErrorMapper) TestBeginError() {
+	errBegin := &pgError{code: "53300"}
+	slf.mock.ExpectBegin().WillReturnError(errBegin)
+
+	err := slf.impl.InTx(context.Background(), func(*mockWithTx) error { return nil })
+	slf.Require().ErrorIs(err, errBegin)
+	slf.Require().NotErrorIs(err, errDuplicate)
+	slf.Equal(1, slf.mapped)
+}
+
+func (slf *ErrorMapper) TestCommitError() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit().WillReturnError(&pgError{code: "23505"})
+
+	err := slf.impl.InTx(context.Background(), func(*mockWithTx) error { return nil })
+	slf.Require().ErrorIs(err, errDuplicate)
+	slf.Equal(1, slf.mapped)
+}
+
+func (slf *ErrorMapper) TestSuccess() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(context.Background(), func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+	slf.Zero(slf.mapped)
+}
+
+func TestErrorMapper(t *testing.T) {
+	suite.Run(t, new(ErrorMapper))
+}
@@ -0,0 +1,59 @@
+package trm
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNoTransaction = errors.New("trm: no transaction in context")
+	ErrNestedTx      = errors.New("trm: transaction started inside another transaction")
+
+	// ErrReadOnlyTransaction is matched by errors.Is when a write failed because the transaction
+	// is read-only on the server (SQLSTATE 25006), e.g. it was routed to a replica or to a primary
+	// demoted by a failover. It usually means the topology in use is stale.
+	ErrReadOnlyTransaction = errors.New("trm: read-only transaction")
+
+	// ErrTxAborted is matched by errors.Is when the callback failed with sql.ErrTxDone:
+	// the transaction was already committed or rolled back under it, e.g. by a nested
+	// operation using RawTx, so the callback error is the consequence, not the cause.
+	ErrTxAborted = errors.New("trm: transaction aborted")
+
+	// ErrQueryMismatch is returned by SameQuery when repositories composed into one
+	// adapter were built from different handles and would not share a transaction.
+	ErrQueryMismatch = errors.New("trm: repositories built from different queries")
+)
+
+// BeginError marks an error returned by the database while beginning a transaction:
+// nothing has been executed yet, so it is always safe to retry.
+type BeginError struct {
+	Err error
+}
+
+func (e *BeginError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BeginError) Unwrap() error {
+	return e.Err
+}
+
+// markReadOnly makes err match ErrReadOnlyTransaction when the database rejected a write
+// in a read-only transaction.
+func markReadOnly(err error) error {
+	if err == nil || SQLState(err) != "25006" || errors.Is(err, ErrReadOnlyTransaction) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", ErrReadOnlyTransaction, err)
+}
+
+// callbackError wraps err returned by the callback.
+func callbackError(err error) error {
+	if errors.Is(err, sql.ErrTxDone) {
+		return fmt.Errorf("%w: trm callback: %w", ErrTxAborted, err)
+	}
+
+	return fmt.Errorf("trm callback: %w", err)
+}
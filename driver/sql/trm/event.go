@@ -0,0 +1,91 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type EventKind int
+
+const (
+	EventBegin EventKind = iota + 1
+	EventCommit
+	EventRollback
+	EventRetry
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventBegin:
+		return "begin"
+	case EventCommit:
+		return "commit"
+	case EventRollback:
+		return "rollback"
+	case EventRetry:
+		return "retry"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes a transaction lifecycle step reported to the event sink.
+// TxID identifies the transaction, see TxID.
+// Err is the begin or commit error, or the error that caused the rollback.
+// Class is the classification of Err for rollback and retry events (see WithErrorClassifier).
+// Attempt is set for retry events: the 1-based number of the attempt that failed with Err
+// and is about to be run again, which makes retries per operation easy to count.
+// TxOptions are the resolved options a begin event was started with, so tests
+// can assert e.g. the isolation level a code path requests.
+// Caller is the location that started the transaction, set with WithCallerInfo.
+// UnitOfWork identifies the operation the transaction is part of, see WithUnitOfWork.
+// LockWait is the time a committed or rolled back transaction was blocked by locks, see WithLockWaitSampling.
+// Reason tells why the transaction of a rollback event was rolled back.
+// RowsScanned is the number of rows a committed or rolled back transaction iterated, see CountRows.
+type Event struct {
+	Kind        EventKind
+	TxID        string
+	Err         error
+	Class       string
+	Attempt     int
+	TxOptions   *sql.TxOptions
+	Caller      string
+	UnitOfWork  string
+	LockWait    time.Duration
+	Reason      RollbackReason
+	RowsScanned int64
+}
+
+// WithEventSink reports transaction lifecycle events to sink.
+//
+// Rollback events are reported with the context built by WithRollbackContext,
+// so trace and logger values survive a cancelled operation context.
+func WithEventSink(sink func(ctx context.Context, e Event)) Option {
+	return func(c *config) {
+		c.sink = sink
+	}
+}
+
+// WithRollbackContext customizes the context rollback events are reported with.
+// By default it is context.WithoutCancel of the operation context.
+func WithRollbackContext(fn func(parent context.Context) context.Context) Option {
+	return func(c *config) {
+		c.rollbackCtx = fn
+	}
+}
+
+func (slf *config) emit(ctx context.Context, st *txState, e Event) {
+	if slf.sink == nil || st != nil && st.dryRun {
+		return
+	}
+
+	if st != nil {
+		e.TxID = st.id()
+	}
+
+	e.Caller = Caller(ctx)
+	e.UnitOfWork = UnitOfWork(ctx)
+
+	slf.sink(ctx, e)
+}
@@ -0,0 +1,145 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type traceKey struct{}
+
+type sinkCall struct {
+	ctx   context.Context
+	event trm.Event
+}
+
+type EventSink struct {
+	suite.Suite
+
+	ctx    context.Context
+	mock   sqlmock.Sqlmock
+	events []sinkCall
+	sink   func(ctx context.Context, e trm.Event)
+}
+
+func (slf *EventSink) SetupTest() {
+	slf.ctx = context.WithValue(context.Background(), traceKey{}, "trace-1")
+	slf.events = nil
+	slf.sink = func(ctx context.Context, e trm.Event) {
+		slf.events = append(slf.events, sinkCall{ctx: ctx, event: e})
+	}
+}
+
+func (slf *EventSink) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *EventSink) newImpl(opts ...trm.Option) *trm.Impl[*mockWithTx] {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+	slf.mock = mock
+
+	return trm.New(db, &mockWithTx{}, append([]trm.Option{trm.WithEventSink(slf.sink)}, opts...)...)
+}
+
+func (slf *EventSink) kinds() []trm.EventKind {
+	kinds := make([]trm.EventKind, 0, len(slf.events))
+	for _, c := range slf.events {
+		kinds = append(kinds, c.event.Kind)
+	}
+
+	return kinds
+}
+
+func (slf *EventSink) TestCommit() {
+	impl := slf.newImpl()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := impl.InTx(slf.ctx, func(_ *mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+	slf.Equal([]trm.EventKind{trm.EventBegin, trm.EventCommit}, slf.kinds())
+}
+
+func (slf *EventSink) TestRollbackOnCancelledContextKeepsTrace() {
+	impl := slf.newImpl()
+	ctx, cancel := context.WithCancel(slf.ctx)
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := impl.InTx(ctx, func(_ *mockWithTx) error {
+		cancel()
+		return context.Canceled
+	})
+	slf.Require().ErrorIs(err, context.Canceled)
+
+	slf.Require().Equal([]trm.EventKind{trm.EventBegin, trm.EventRollback}, slf.kinds())
+	rollback := slf.events[1]
+	slf.Require().NoError(rollback.ctx.Err())
+	slf.Equal("trace-1", rollback.ctx.Value(traceKey{}))
+	slf.ErrorIs(rollback.event.Err, context.Canceled)
+}
+
+func (slf *EventSink) TestCustomRollbackContext() {
+	impl := slf.newImpl(trm.WithRollbackContext(func(parent context.Context) context.Context {
+		return context.WithValue(context.WithoutCancel(parent), traceKey{}, "custom")
+	}))
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := impl.InTx(slf.ctx, func(_ *mockWithTx) error { return errors.New("err") })
+	slf.Require().Error(err)
+	slf.Equal("custom", slf.events[1].ctx.Value(traceKey{}))
+}
+
+func (slf *EventSink) TestBeginError() {
+	impl := slf.newImpl()
+	slf.mock.ExpectBegin().WillReturnError(errors.New("err"))
+
+	err := impl.InTx(slf.ctx, func(_ *mockWithTx) error { return nil })
+	slf.Require().Error(err)
+	slf.Require().Len(slf.events, 1)
+	slf.Equal(trm.EventBegin, slf.events[0].event.Kind)
+	slf.EqualError(slf.events[0].event.Err, "err")
+}
+
+func (slf *EventSink) TestRetry() {
+	impl := slf.newImpl(
+		trm.WithRollbackDecider(func(_ context.Context, attempt int, _ error) bool { return attempt < 2 }),
+		trm.WithErrorClassifier(func(error) string { return "serialization_failure" }),
+	)
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	calls := 0
+	err := impl.InTx(slf.ctx, func(_ *mockWithTx) error {
+		calls++
+		if calls == 1 {
+			return errors.New("conflict")
+		}
+
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal([]trm.EventKind{
+		trm.EventBegin, trm.EventRollback, trm.EventRetry, trm.EventBegin, trm.EventCommit,
+	}, slf.kinds())
+
+	retry := slf.events[2].event
+	slf.Equal(1, retry.Attempt)
+	slf.Equal("serialization_failure", retry.Class)
+	slf.EqualError(retry.Err, "trm callback: conflict")
+	slf.Equal(slf.events[1].event.TxID, retry.TxID)
+}
+
+func TestEventSink(t *testing.T) {
+	suite.Run(t, new(EventSink))
+}
@@ -0,0 +1,191 @@
+package trm
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// maxExplained bounds the number of statements recorded per transaction for WithAutoExplain.
+	maxExplained = 32
+	// explainTimeout bounds the time spent collecting the plans of a transaction.
+	explainTimeout = time.Minute
+)
+
+// WithAutoExplain captures the query plans of transactions slower than threshold and passes
+// them to report, turning slow transaction alerts into actionable plans.
+//
+// Only a rate fraction (0 to 1) of transactions is sampled: statements of other transactions
+// are not even recorded. Once a sampled transaction committed or rolled back after running
+// for longer than threshold, every statement it executed (up to the first 32) is run again as
+// EXPLAIN (ANALYZE, BUFFERS) with the same arguments, each in its own read-only transaction
+// that is always rolled back, so writes fail to explain instead of being repeated; their
+// failure is reported in ExplainPlan.Err.
+//
+// EXPLAIN ANALYZE executes the statement, so this is expensive: the plans are collected in the
+// background after InTx returned, for one transaction at a time per transactor, within a minute.
+// Slow transactions ending while plans are being collected are not explained, nor are those whose
+// InTx context is done, e.g. cancelled because the database was overloaded.
+// Transactions of a Session are not explained either, as their connection cannot be shared.
+// PostgreSQL syntax; prepared statements are not recorded.
+func WithAutoExplain(
+	threshold time.Duration,
+	rate float64,
+	report func(ctx context.Context, plans []ExplainPlan),
+) Option {
+	return func(c *config) {
+		c.explain = &autoExplain{threshold: threshold, report: report}
+		c.wrappers = append(c.wrappers, func(st *txState, tx Transaction) Transaction {
+			if !st.sampled || rand.Float64() >= rate { //nolint:gosec // sampling needs no cryptographic randomness
+				return tx
+			}
+
+			st.explained = &explainBuffer{}
+
+			return &explainTx{Transaction: tx, buf: st.explained}
+		})
+	}
+}
+
+// ExplainPlan is the plan of a statement of a slow transaction, see WithAutoExplain.
+type ExplainPlan struct {
+	Query string
+	// Plan is the text of EXPLAIN, one plan line per line.
+	Plan string
+	Err  error
+}
+
+type autoExplain struct {
+	threshold time.Duration
+	report    func(ctx context.Context, plans []ExplainPlan)
+	// running is set while the plans of a transaction are being collected.
+	running atomic.Bool
+}
+
+// explain starts reporting the plans of the statements of st when it ran for longer than the threshold.
+func (slf *autoExplain) explain(ctx context.Context, db beginner, st *txState) {
+	if slf == nil || st == nil || st.explained == nil || st.startTime.IsZero() ||
+		time.Since(st.startTime) < slf.threshold || ctx.Err() != nil {
+		return
+	}
+
+	pool, ok := db.(*sql.DB)
+	if !ok {
+		return
+	}
+
+	stmts := st.explained.statements()
+	if len(stmts) == 0 || !slf.running.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer slf.running.Store(false)
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), explainTimeout)
+		defer cancel()
+
+		plans := make([]ExplainPlan, len(stmts))
+		for i, s := range stmts {
+			plans[i] = ExplainPlan{Query: s.query}
+			plans[i].Plan, plans[i].Err = explainStatement(ctx, pool, s)
+		}
+
+		slf.report(ctx, plans)
+	}()
+}
+
+func explainStatement(ctx context.Context, db *sql.DB, s explainedStatement) (string, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, "EXPLAIN (ANALYZE, BUFFERS) "+s.query, s.args...)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+
+		err = rows.Scan(&line)
+		if err != nil {
+			return "", err
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n"), rows.Err()
+}
+
+type explainedStatement struct {
+	query string
+	args  []any
+}
+
+type explainBuffer struct {
+	mu    sync.Mutex
+	stmts []explainedStatement
+}
+
+func (slf *explainBuffer) record(query string, args []any) {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	if len(slf.stmts) >= maxExplained {
+		return
+	}
+
+	kept := make([]any, len(args))
+	for i, arg := range args {
+		if b, ok := arg.([]byte); ok {
+			// The statement is explained after InTx returned, when the caller may have reused its buffer.
+			arg = bytes.Clone(b)
+		}
+
+		kept[i] = arg
+	}
+
+	slf.stmts = append(slf.stmts, explainedStatement{query: query, args: kept})
+}
+
+func (slf *explainBuffer) statements() []explainedStatement {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	return slf.stmts
+}
+
+type explainTx struct {
+	Transaction
+
+	buf *explainBuffer
+}
+
+func (slf *explainTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	slf.buf.record(query, args)
+	return slf.Transaction.ExecContext(ctx, query, args...)
+}
+
+func (slf *explainTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	slf.buf.record(query, args)
+	return slf.Transaction.QueryContext(ctx, query, args...)
+}
+
+func (slf *explainTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	slf.buf.record(query, args)
+	return slf.Transaction.QueryRowContext(ctx, query, args...)
+}
@@ -0,0 +1,204 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type AutoExplain struct {
+	suite.Suite
+
+	mock  sqlmock.Sqlmock
+	plans chan []trm.ExplainPlan
+	// release, when set, blocks reports until it is closed.
+	release chan struct{}
+}
+
+func (slf *AutoExplain) SetupTest() {
+	slf.plans = make(chan []trm.ExplainPlan, 1)
+	slf.release = nil
+}
+
+func (slf *AutoExplain) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *AutoExplain) newImpl(threshold time.Duration, rate float64) *trm.Impl[*txRepo] {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+
+	release := slf.release
+
+	return trm.New(db, &txRepo{}, trm.WithAutoExplain(threshold, rate, func(_ context.Context, plans []trm.ExplainPlan) {
+		slf.plans <- plans
+
+		if release != nil {
+			<-release
+		}
+	}))
+}
+
+func (slf *AutoExplain) run(impl *trm.Impl[*txRepo]) {
+	err := impl.InTx(context.Background(), func(r *txRepo) error {
+		_, err := r.tx.ExecContext(context.Background(), "UPDATE t SET a = $1", 1)
+		slf.Require().NoError(err)
+
+		rows, err := r.tx.QueryContext(context.Background(), "SELECT a FROM t")
+		slf.Require().NoError(err)
+
+		return rows.Close()
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *AutoExplain) expectTx() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("UPDATE t SET a = $1").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectQuery("SELECT a FROM t").WillReturnRows(sqlmock.NewRows([]string{"a"}))
+	slf.mock.ExpectCommit()
+}
+
+func (slf *AutoExplain) expectExplain() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery("EXPLAIN (ANALYZE, BUFFERS) UPDATE t SET a = $1").WithArgs(1).WillReturnError(errors.New("read-only"))
+	slf.mock.ExpectRollback()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery("EXPLAIN (ANALYZE, BUFFERS) SELECT a FROM t").WillReturnRows(sqlmock.NewRows([]string{"QUERY PLAN"}))
+	slf.mock.ExpectRollback()
+}
+
+func (slf *AutoExplain) TestSlowTransaction() {
+	impl := slf.newImpl(0, 1)
+	readOnly := errors.New("cannot execute UPDATE in a read-only transaction")
+
+	slf.expectTx()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery("EXPLAIN (ANALYZE, BUFFERS) UPDATE t SET a = $1").WithArgs(1).WillReturnError(readOnly)
+	slf.mock.ExpectRollback()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery("EXPLAIN (ANALYZE, BUFFERS) SELECT a FROM t").
+		WillReturnRows(sqlmock.NewRows([]string{"QUERY PLAN"}).AddRow("Seq Scan on t").AddRow("Execution Time: 0.1 ms"))
+	slf.mock.ExpectRollback()
+
+	slf.run(impl)
+
+	plans := <-slf.plans
+	slf.Require().Len(plans, 2)
+	slf.Equal("UPDATE t SET a = $1", plans[0].Query)
+	slf.ErrorIs(plans[0].Err, readOnly)
+	slf.Equal(trm.ExplainPlan{Query: "SELECT a FROM t", Plan: "Seq Scan on t\nExecution Time: 0.1 ms"}, plans[1])
+}
+
+func (slf *AutoExplain) TestOneTransactionAtATime() {
+	slf.release = make(chan struct{})
+	impl := slf.newImpl(0, 1)
+
+	slf.expectTx()
+	slf.expectExplain()
+	slf.run(impl)
+	slf.Len(<-slf.plans, 2)
+
+	// The plans of the first transaction are still being reported.
+	slf.expectTx()
+	slf.run(impl)
+	close(slf.release)
+	slf.Empty(slf.plans)
+}
+
+func (slf *AutoExplain) TestCancelledNotExplained() {
+	impl := slf.newImpl(0, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := impl.InTx(ctx, func(r *txRepo) error {
+		cancel()
+
+		_, err := r.tx.ExecContext(ctx, "UPDATE t SET a = $1", 1)
+
+		return err
+	})
+	slf.Require().ErrorIs(err, context.Canceled)
+	slf.Empty(slf.plans)
+}
+
+func (slf *AutoExplain) TestFastTransaction() {
+	impl := slf.newImpl(time.Hour, 1)
+
+	slf.expectTx()
+	slf.run(impl)
+	slf.Empty(slf.plans)
+}
+
+func (slf *AutoExplain) TestNotSampled() {
+	impl := slf.newImpl(0, 0)
+
+	slf.expectTx()
+	slf.run(impl)
+	slf.Empty(slf.plans)
+}
+
+func (slf *AutoExplain) TestSessionNotExplained() {
+	impl := slf.newImpl(0, 1)
+
+	slf.expectTx()
+
+	err := impl.WithSession(context.Background(), func(sess *trm.Session[*txRepo]) error {
+		return sess.InTx(context.Background(), func(r *txRepo) error {
+			_, err := r.tx.ExecContext(context.Background(), "UPDATE t SET a = $1", 1)
+			slf.Require().NoError(err)
+
+			rows, err := r.tx.QueryContext(context.Background(), "SELECT a FROM t")
+			slf.Require().NoError(err)
+
+			return rows.Close()
+		})
+	})
+	slf.Require().NoError(err)
+
+	select {
+	case plans := <-slf.plans:
+		slf.Failf("session transaction explained", "%v", plans)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func (slf *AutoExplain) TestReusedBuffer() {
+	impl := slf.newImpl(0, 1)
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("UPDATE t SET a = $1").WithArgs([]byte("old")).WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectCommit()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery("EXPLAIN (ANALYZE, BUFFERS) UPDATE t SET a = $1").
+		WithArgs([]byte("old")).
+		WillReturnRows(sqlmock.NewRows([]string{"QUERY PLAN"}).AddRow("Update on t"))
+	slf.mock.ExpectRollback()
+
+	err := impl.InTx(context.Background(), func(r *txRepo) error {
+		buf := []byte("old")
+		_, err := r.tx.ExecContext(context.Background(), "UPDATE t SET a = $1", buf)
+		copy(buf, "new")
+
+		return err
+	})
+	slf.Require().NoError(err)
+
+	plans := <-slf.plans
+	slf.Require().Len(plans, 1)
+	slf.NoError(plans[0].Err)
+}
+
+func TestAutoExplain(t *testing.T) {
+	suite.Run(t, new(AutoExplain))
+}
@@ -1,3 +1,12 @@
 package trm
 
+import (
+	"context"
+	"database/sql"
+)
+
 type Impl[T any] = impl[T]
+
+func TxOptionsOf[T any](impl *Impl[T], ctx context.Context, opts ...CallOption) *sql.TxOptions {
+	return impl.cfg.txOptions(ctx, newCall(opts))
+}
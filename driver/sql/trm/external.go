@@ -0,0 +1,88 @@
+package trm
+
+import (
+	"context"
+	"time"
+)
+
+// WithExternalTx lets a transaction owned by a parent framework, e.g. the unit of work of a workflow
+// activity, stand in for the one InTx would begin. provide is called for every attempt; when it
+// returns true, the callback runs on tx, and the transactor calls commit or rollback instead of
+// ending the transaction itself, so the framework decides what they do. Otherwise the transactor
+// begins its own transaction as usual.
+//
+// As the transactor did not begin it, setup options (statement timeouts, session variables,
+// advisory locks, InTxLocking), WithLockWaitSampling and RawTx do not apply to the external
+// transaction, and no begin event is emitted; savepoints, EnqueueOutbox and the Batcher run on it.
+// The methods of tx other than those of Query are not used.
+func WithExternalTx(
+	provide func(ctx context.Context) (tx Transaction, commit func() error, rollback func() error, ok bool),
+) Option {
+	return func(c *config) {
+		c.externalTx = provide
+	}
+}
+
+// externalTx is the Transaction of WithExternalTx, ended by the framework owning it.
+type externalTx struct {
+	Query
+
+	commit   func() error
+	rollback func() error
+}
+
+func (slf *externalTx) Commit() error {
+	return slf.commit()
+}
+
+func (slf *externalTx) Rollback() error {
+	return slf.rollback()
+}
+
+// startExternal makes the transaction provided by WithExternalTx the one of st, if any.
+func (slf *impl[T]) startExternal(ctx context.Context, st *txState) bool {
+	if slf.cfg.externalTx == nil {
+		return false
+	}
+
+	tx, commit, rollback, ok := slf.cfg.externalTx(ctx)
+	if !ok {
+		return false
+	}
+
+	st.stats.inFlight.Add(1)
+	st.external = &externalTx{Query: tx, commit: commit, rollback: rollback}
+	st.startTime = time.Now()
+	st.txn = slf.cfg.wrap(st, st.external)
+
+	return true
+}
+
+// rollbackTarget returns the transaction of st to roll back.
+func (slf *txState) rollbackTarget() Transaction {
+	if slf.external != nil {
+		return slf.external
+	}
+
+	return slf.tx
+}
+
+// baseTx returns the transaction of st below the wrappers: the external one of WithExternalTx or
+// the one begun by the transactor, beginning it first with WithLazyBegin.
+func (slf *txState) baseTx() (Query, error) {
+	if slf.external != nil {
+		return slf.external, nil
+	}
+
+	tx, err := slf.rawTx()
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// begun reports whether st holds a transaction to end, begun by the transactor or external.
+func (slf *txState) begun() bool {
+	return slf.tx != nil || slf.external != nil
+}
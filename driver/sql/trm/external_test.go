@@ -0,0 +1,151 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type externalKey struct{}
+
+type ExternalTx struct {
+	suite.Suite
+
+	ctx  context.Context
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+	ends []string
+}
+
+func (slf *ExternalTx) SetupTest() {
+	var err error
+
+	slf.db, slf.mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.ends = nil
+	slf.impl = slf.newImpl()
+}
+
+func (slf *ExternalTx) newImpl(opts ...trm.Option) *trm.Impl[*txRepo] {
+	return trm.New(slf.db, &txRepo{}, append(opts, trm.WithExternalTx(
+		func(ctx context.Context) (trm.Transaction, func() error, func() error, bool) {
+			tx, ok := ctx.Value(externalKey{}).(*sql.Tx)
+			if !ok {
+				return nil, nil, nil, false
+			}
+
+			commit := func() error {
+				slf.ends = append(slf.ends, "commit")
+				return nil
+			}
+			rollback := func() error {
+				slf.ends = append(slf.ends, "rollback")
+				return nil
+			}
+
+			return tx, commit, rollback, true
+		},
+	))...)
+}
+
+func (slf *ExternalTx) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+// external begins the transaction of the parent framework and returns a context carrying it.
+func (slf *ExternalTx) external() context.Context {
+	slf.mock.ExpectBegin()
+
+	tx, err := slf.db.BeginTx(slf.ctx, nil)
+	slf.Require().NoError(err)
+
+	return context.WithValue(slf.ctx, externalKey{}, tx)
+}
+
+func (slf *ExternalTx) TestNoDeterminismDryRun() {
+	ctx := slf.external()
+
+	var warnings []error
+	impl := slf.newImpl(trm.WithDeterminismCheck(func(_ context.Context, err error) {
+		warnings = append(warnings, err)
+	}))
+
+	runs := 0
+	err := impl.InTx(ctx, func(*txRepo) error {
+		runs++
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal(1, runs)
+	slf.Equal([]string{"commit"}, slf.ends)
+	slf.Empty(warnings)
+}
+
+func (slf *ExternalTx) TestCommitDelegated() {
+	ctx := slf.external()
+	slf.mock.ExpectExec("INSERT INTO a VALUES (1)").WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := slf.impl.InTx(ctx, func(r *txRepo) error {
+		_, err := r.tx.ExecContext(ctx, "INSERT INTO a VALUES (1)")
+		return err
+	})
+	slf.Require().NoError(err)
+	slf.Equal([]string{"commit"}, slf.ends)
+}
+
+func (slf *ExternalTx) TestRollbackDelegated() {
+	ctx := slf.external()
+	errFailed := errors.New("failed")
+
+	err := slf.impl.InTx(ctx, func(*txRepo) error { return errFailed })
+	slf.Require().ErrorIs(err, errFailed)
+	slf.Equal([]string{"rollback"}, slf.ends)
+}
+
+func (slf *ExternalTx) TestOwnTransactionWithoutExternal() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(slf.ctx, func(*txRepo) error { return nil })
+	slf.Require().NoError(err)
+	slf.Empty(slf.ends)
+}
+
+func (slf *ExternalTx) TestSavepointAndOutbox() {
+	ctx := slf.external()
+	slf.mock.ExpectExec("INSERT INTO \"outbox\" (topic, payload) VALUES ($1, $2)").
+		WithArgs("orders", []byte("{}")).WillReturnResult(sqlmock.NewResult(1, 1))
+	slf.mock.ExpectExec("SAVEPOINT sp_1_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectExec("RELEASE SAVEPOINT sp_1_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	notified := false
+	impl := slf.newImpl(
+		trm.WithIDGenerator(func() string { return "tx" }),
+		trm.WithOutbox("outbox", func(context.Context) { notified = true }),
+	)
+
+	err := impl.InTxCtx(ctx, func(ctx context.Context, _ *txRepo) error {
+		err := trm.EnqueueOutbox(ctx, "orders", []byte("{}"))
+		if err != nil {
+			return err
+		}
+
+		return trm.NewSavepoint[*txRepo](ctx).Run(func(*txRepo) error { return nil })
+	})
+	slf.Require().NoError(err)
+	slf.True(notified)
+	slf.Equal([]string{"commit"}, slf.ends)
+}
+
+func TestExternalTx(t *testing.T) {
+	suite.Run(t, new(ExternalTx))
+}
@@ -0,0 +1,53 @@
+package trm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type InTxExtra struct {
+	suite.Suite
+
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *InTxExtra) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+	slf.impl = trm.New(db, &txRepo{}, trm.WithRowsAffected())
+}
+
+func (slf *InTxExtra) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *InTxExtra) TestSharesTransaction() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("UPDATE audit SET n = n + 1").WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectCommit()
+
+	var audit *txRepo
+	err := slf.impl.InTxExtra(context.Background(), func(tx trm.Transaction) {
+		audit = (&txRepo{}).WithTx(tx)
+	}, func(r *txRepo) error {
+		slf.Require().NotNil(audit)
+		slf.Same(r.tx, audit.tx)
+
+		_, err := audit.tx.ExecContext(context.Background(), "UPDATE audit SET n = n + 1")
+
+		return err
+	})
+	slf.Require().NoError(err)
+}
+
+func TestInTxExtra(t *testing.T) {
+	suite.Run(t, new(InTxExtra))
+}
@@ -0,0 +1,52 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+)
+
+// WithFailFast returns ctx.Err() from every statement executed through the transaction
+// when its context is already done, without reaching the database.
+//
+// database/sql performs the same check before using the connection; registered last, this
+// wrapper makes it the outermost layer, so inner wrappers (query tags, the statement cache,
+// counters) do no work for an abandoned request either. QueryRowContext is passed through,
+// as *sql.Row cannot carry the error and database/sql fails it the same way.
+func WithFailFast() Option {
+	return func(c *config) {
+		c.wrappers = append(c.wrappers, func(_ *txState, tx Transaction) Transaction {
+			return &failFastTx{Transaction: tx}
+		})
+	}
+}
+
+type failFastTx struct {
+	Transaction
+}
+
+func (slf *failFastTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return slf.Transaction.ExecContext(ctx, query, args...)
+}
+
+func (slf *failFastTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return slf.Transaction.PrepareContext(ctx, query)
+}
+
+func (slf *failFastTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return slf.Transaction.QueryContext(ctx, query, args...)
+}
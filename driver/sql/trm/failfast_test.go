@@ -0,0 +1,56 @@
+package trm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type FailFast struct {
+	suite.Suite
+
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *FailFast) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+	slf.impl = trm.New(db, &txRepo{}, trm.WithStmtCache(), trm.WithFailFast())
+}
+
+func (slf *FailFast) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *FailFast) TestCancelledStatementNotSent() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(context.Background(), func(r *txRepo) error {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := r.tx.ExecContext(ctx, "UPDATE users SET name = ''")
+		slf.Require().ErrorIs(err, context.Canceled)
+
+		_, err = r.tx.QueryContext(ctx, "SELECT 1")
+		slf.Require().ErrorIs(err, context.Canceled)
+
+		_, err = r.tx.PrepareContext(ctx, "SELECT 1")
+		slf.Require().ErrorIs(err, context.Canceled)
+
+		return err
+	})
+	slf.Require().ErrorIs(err, context.Canceled)
+}
+
+func TestFailFast(t *testing.T) {
+	suite.Run(t, new(FailFast))
+}
@@ -0,0 +1,61 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// FaultOp is an operation WithFaultInjector can fail. The injector itself is only
+// compiled with the trmfaults build tag, so it cannot ship enabled by accident.
+type FaultOp int
+
+const (
+	FaultBegin FaultOp = iota + 1
+	FaultCommit
+	FaultRollback
+)
+
+func (op FaultOp) String() string {
+	switch op {
+	case FaultBegin:
+		return "begin"
+	case FaultCommit:
+		return "commit"
+	case FaultRollback:
+		return "rollback"
+	default:
+		return "unknown"
+	}
+}
+
+// fault returns the error injected into op, if any.
+func (slf *config) fault(op FaultOp) error {
+	if slf.faults == nil {
+		return nil
+	}
+
+	return slf.faults(op)
+}
+
+// beginTx begins a transaction on db unless a begin fault is injected.
+func (slf *config) beginTx(ctx context.Context, db beginner, opts *sql.TxOptions) (*sql.Tx, error) {
+	err := slf.fault(FaultBegin)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.BeginTx(ctx, opts)
+}
+
+// rollbackTx rolls tx back and returns cause joined with an injected rollback fault, if any.
+func (slf *config) rollbackTx(tx Transaction, cause error) error {
+	_ = tx.Rollback()
+
+	err := slf.fault(FaultRollback)
+	if err != nil {
+		return errors.Join(cause, err)
+	}
+
+	return cause
+}
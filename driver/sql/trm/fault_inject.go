@@ -0,0 +1,63 @@
+//go:build trmfaults
+
+package trm
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync/atomic"
+)
+
+var ErrInjectedFault = errors.New("trm: injected fault")
+
+// FaultConfig describes the failures WithFaultInjector injects.
+type FaultConfig struct {
+	// Begin, Commit and Rollback are the probabilities (0 to 1) of failing each operation.
+	Begin    float64
+	Commit   float64
+	Rollback float64
+	// Schedule, if not nil, replaces the probabilities with a deterministic schedule: it receives
+	// the operation and its 1-based number among the operations of that kind, and returns whether
+	// it fails.
+	Schedule func(op FaultOp, n int) bool
+}
+
+// WithFaultInjector fails begins, commits and rollbacks as described by fc with ErrInjectedFault,
+// for chaos tests of retry and circuit breaker logic. It is only compiled with the trmfaults
+// build tag (go test -tags trmfaults), so production builds cannot enable it.
+//
+// A failed begin is reported as a *BeginError and is retried by OnBeginFailure. A failed commit
+// rolls the transaction back and fails InTx. A rollback still rolls back, to free the connection,
+// but the fault is joined to the error of the rollback event, as rollback errors never fail InTx.
+func WithFaultInjector(fc FaultConfig) Option {
+	return func(c *config) {
+		var seq [FaultRollback + 1]atomic.Int64
+
+		c.faults = func(op FaultOp) error {
+			if !fc.fails(op, int(seq[op].Add(1))) {
+				return nil
+			}
+
+			return fmt.Errorf("%w: %s", ErrInjectedFault, op)
+		}
+	}
+}
+
+func (slf FaultConfig) fails(op FaultOp, n int) bool {
+	if slf.Schedule != nil {
+		return slf.Schedule(op, n)
+	}
+
+	var p float64
+	switch op {
+	case FaultBegin:
+		p = slf.Begin
+	case FaultCommit:
+		p = slf.Commit
+	case FaultRollback:
+		p = slf.Rollback
+	}
+
+	return rand.Float64() < p //nolint:gosec // fault injection needs no cryptographic randomness
+}
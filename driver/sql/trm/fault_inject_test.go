@@ -0,0 +1,96 @@
+//go:build trmfaults
+
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type FaultInjector struct {
+	suite.Suite
+
+	mock   sqlmock.Sqlmock
+	events []trm.Event
+}
+
+func (slf *FaultInjector) SetupTest() {
+	slf.events = nil
+}
+
+func (slf *FaultInjector) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *FaultInjector) newImpl(fc trm.FaultConfig, opts ...trm.Option) *trm.Impl[*mockWithTx] {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+	opts = append(opts, trm.WithFaultInjector(fc), trm.WithEventSink(func(_ context.Context, e trm.Event) {
+		slf.events = append(slf.events, e)
+	}))
+
+	return trm.New(db, &mockWithTx{}, opts...)
+}
+
+func (slf *FaultInjector) TestBeginScheduleRetried() {
+	impl := slf.newImpl(trm.FaultConfig{Schedule: func(op trm.FaultOp, n int) bool {
+		return op == trm.FaultBegin && n == 1
+	}}, trm.OnBeginFailure(func(_ context.Context, err error) bool {
+		return errors.Is(err, trm.ErrInjectedFault)
+	}))
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := impl.InTx(context.Background(), func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+	slf.Equal(int64(1), impl.Stats().BeginFailures)
+}
+
+func (slf *FaultInjector) TestCommit() {
+	impl := slf.newImpl(trm.FaultConfig{Commit: 1})
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := impl.InTx(context.Background(), func(*mockWithTx) error { return nil })
+	slf.Require().ErrorIs(err, trm.ErrInjectedFault)
+	slf.Require().EqualError(err, "commit tx: trm: injected fault: commit")
+}
+
+func (slf *FaultInjector) TestRollback() {
+	impl := slf.newImpl(trm.FaultConfig{Rollback: 1})
+	cause := errors.New("cause")
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := impl.InTx(context.Background(), func(*mockWithTx) error { return cause })
+	slf.Require().ErrorIs(err, cause)
+	slf.NotErrorIs(err, trm.ErrInjectedFault)
+	slf.Require().Len(slf.events, 2)
+	slf.ErrorIs(slf.events[1].Err, cause)
+	slf.ErrorIs(slf.events[1].Err, trm.ErrInjectedFault)
+}
+
+func (slf *FaultInjector) TestZeroRates() {
+	impl := slf.newImpl(trm.FaultConfig{})
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := impl.InTx(context.Background(), func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func TestFaultInjector(t *testing.T) {
+	suite.Run(t, new(FaultInjector))
+}
@@ -0,0 +1,54 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var ErrWriteForbidden = errors.New("trm: write forbidden by read-only guard")
+
+type readOnlyGuard[T withTx[T]] struct {
+	base *impl[T]
+}
+
+// ReadOnlyGuard makes the transactions of base read-only twice: they are begun with
+// TxOptions.ReadOnly set, the isolation level being kept, and reject statements that may write
+// before they reach the database: ExecContext and PrepareContext return ErrWriteForbidden,
+// QueryContext and QueryRowContext pass through. The statements of savepoints and EnqueueOutbox
+// go through the same check, and RawTx reports no transaction.
+//
+// It is an application-level check complementing the read-only transaction, e.g. for
+// a reporting service that must never write, failing accidental writes with a clear error.
+// Statements that write through QueryContext, e.g. INSERT ... RETURNING, are left to the database.
+// Other transactors started from the callback context are not guarded.
+//
+//nolint:revive // takes and returns hidden implementation types, the guard only applies to base
+func ReadOnlyGuard[T withTx[T]](base *impl[T]) *readOnlyGuard[T] {
+	return &readOnlyGuard[T]{base: base}
+}
+
+// InTx runs fn in a guarded transaction of base, see tr.Transactor.
+func (slf *readOnlyGuard[T]) InTx(ctx context.Context, fn func(T) error) error {
+	return slf.InTxCtx(ctx, func(_ context.Context, repo T) error {
+		return fn(repo)
+	})
+}
+
+// InTxCtx is InTx for callbacks that need the transaction context, see impl.InTxCtx.
+func (slf *readOnlyGuard[T]) InTxCtx(ctx context.Context, fn func(ctx context.Context, repo T) error) error {
+	_, err := slf.base.run(ctx, &call{intent: intentRead, guard: true}, fn)
+	return err
+}
+
+type guardTx struct {
+	Transaction
+}
+
+func (slf *guardTx) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return nil, ErrWriteForbidden
+}
+
+func (slf *guardTx) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, ErrWriteForbidden
+}
@@ -0,0 +1,134 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+	"github.com/metalfm/transactor/tr"
+)
+
+type ReadOnlyGuard struct {
+	suite.Suite
+
+	mock  sqlmock.Sqlmock
+	impl  *trm.Impl[*txRepo]
+	guard tr.Transactor[*txRepo]
+	opts  *sql.TxOptions
+}
+
+func (slf *ReadOnlyGuard) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+	slf.opts = nil
+	slf.impl = trm.New(db, &txRepo{},
+		trm.WithTxOptions(&sql.TxOptions{Isolation: sql.LevelRepeatableRead}),
+		trm.WithOutbox("outbox", func(context.Context) {}),
+		trm.WithIDGenerator(func() string { return "tx" }),
+		trm.WithEventSink(func(_ context.Context, e trm.Event) {
+			if e.Kind == trm.EventBegin {
+				slf.opts = e.TxOptions
+			}
+		}),
+	)
+	slf.guard = trm.ReadOnlyGuard(slf.impl)
+}
+
+func (slf *ReadOnlyGuard) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *ReadOnlyGuard) TestWriteForbidden() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.guard.InTx(context.Background(), func(r *txRepo) error {
+		_, err := r.tx.PrepareContext(context.Background(), "DELETE FROM orders")
+		slf.Require().ErrorIs(err, trm.ErrWriteForbidden)
+
+		_, err = r.tx.ExecContext(context.Background(), "DELETE FROM orders")
+
+		return err
+	})
+	slf.Require().ErrorIs(err, trm.ErrWriteForbidden)
+	slf.Equal(&sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, slf.opts)
+}
+
+func (slf *ReadOnlyGuard) TestBypassesGuarded() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SAVEPOINT sp_1_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit()
+
+	err := trm.ReadOnlyGuard(slf.impl).InTxCtx(context.Background(), func(ctx context.Context, _ *txRepo) error {
+		_, ok := trm.RawTx(ctx)
+		slf.False(ok)
+
+		slf.Require().ErrorIs(trm.EnqueueOutbox(ctx, "orders", []byte("1")), trm.ErrWriteForbidden)
+
+		err := trm.NewSavepoint[*txRepo](ctx).Run(func(r *txRepo) error {
+			_, err := r.tx.ExecContext(ctx, "DELETE FROM orders")
+			return err
+		})
+		slf.Require().ErrorIs(err, trm.ErrWriteForbidden)
+
+		return nil
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *ReadOnlyGuard) TestNotInheritedByOtherTransactors() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM sessions").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	other := trm.New(db, &txRepo{})
+	err = trm.ReadOnlyGuard(slf.impl).InTxCtx(context.Background(), func(ctx context.Context, _ *txRepo) error {
+		return other.InTx(ctx, func(r *txRepo) error {
+			_, err := r.tx.ExecContext(ctx, "DELETE FROM sessions")
+			return err
+		})
+	})
+	slf.Require().NoError(err)
+	slf.NoError(mock.ExpectationsWereMet())
+}
+
+func (slf *ReadOnlyGuard) TestQueryPasses() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery("SELECT id FROM orders").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	slf.mock.ExpectCommit()
+
+	err := slf.guard.InTx(context.Background(), func(r *txRepo) error {
+		var id int
+		return r.tx.QueryRowContext(context.Background(), "SELECT id FROM orders").Scan(&id)
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *ReadOnlyGuard) TestUnguardedWrites() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("DELETE FROM orders").WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(context.Background(), func(r *txRepo) error {
+		_, err := r.tx.ExecContext(context.Background(), "DELETE FROM orders")
+		return err
+	})
+	slf.Require().NoError(err)
+}
+
+func TestReadOnlyGuard(t *testing.T) {
+	suite.Run(t, new(ReadOnlyGuard))
+}
@@ -0,0 +1,82 @@
+package trm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// OnCommit registers fn to run after the transaction carried by ctx commits.
+// Hooks run in registration order with the operation context, still carrying the committed
+// transaction for helpers such as DidModify, and are discarded on rollback,
+// so an attempt that is retried does not leave hooks behind.
+// Hook errors are joined and, by default, returned by InTx wrapped with "on commit", although
+// the data is committed; see WithOnCommitErrorPolicy.
+func OnCommit(ctx context.Context, fn func(ctx context.Context) error) error {
+	st := stateFrom(ctx)
+	if st == nil || st.committed {
+		return fmt.Errorf("on commit: %w", ErrNoTransaction)
+	}
+
+	st.addOnCommit(fn)
+
+	return nil
+}
+
+// OnCommitErrorPolicy decides what InTx does when the transaction committed but OnCommit hooks failed.
+type OnCommitErrorPolicy int
+
+const (
+	// ReturnError returns the hook errors from InTx, although the data is committed. The default.
+	ReturnError OnCommitErrorPolicy = iota
+	// LogOnly passes the hook errors to the log function and returns nil, as the transaction succeeded.
+	LogOnly
+)
+
+// WithOnCommitErrorPolicy sets what InTx does when OnCommit hooks fail after the commit; ReturnError
+// when not set. With LogOnly the errors, wrapped with "on commit", are passed to log, which may be nil
+// to discard them. Callers must then not rely on hooks for anything the operation depends on.
+func WithOnCommitErrorPolicy(policy OnCommitErrorPolicy, log func(ctx context.Context, err error)) Option {
+	return func(c *config) {
+		c.onCommitPolicy = policy
+		c.onCommitLog = log
+	}
+}
+
+// onCommitFailed applies the OnCommitErrorPolicy to the joined errors of the OnCommit hooks.
+func (slf *config) onCommitFailed(ctx context.Context, err error) error {
+	err = fmt.Errorf("on commit: %w", err)
+	if slf.onCommitPolicy == ReturnError {
+		return err
+	}
+
+	if slf.onCommitLog != nil {
+		slf.onCommitLog(ctx, err)
+	}
+
+	return nil
+}
+
+// addOnCommit registers an OnCommit hook; it is safe for concurrent use.
+func (slf *txState) addOnCommit(fn func(ctx context.Context) error) {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	slf.onCommit = append(slf.onCommit, fn)
+}
+
+func (slf *txState) runOnCommit(ctx context.Context) error {
+	slf.mu.Lock()
+	hooks := slf.onCommit
+	slf.mu.Unlock()
+
+	var errs []error
+	for _, fn := range hooks {
+		err := fn(ctx)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,122 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type OnCommit struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+}
+
+func (slf *OnCommit) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithRollbackDecider(func(_ context.Context, attempt int, _ error) bool {
+		return attempt < 2
+	}))
+}
+
+func (slf *OnCommit) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *OnCommit) TestRunsAfterCommitInOrder() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	var calls []string
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		for _, name := range []string{"a", "b"} {
+			err := trm.OnCommit(ctx, func(context.Context) error {
+				calls = append(calls, name)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		slf.Empty(calls)
+
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal([]string{"a", "b"}, calls)
+}
+
+func (slf *OnCommit) TestDiscardedOnRollback() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	calls, attempt := 0, 0
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		attempt++
+		_ = trm.OnCommit(ctx, func(context.Context) error {
+			calls++
+			return nil
+		})
+		if attempt == 1 {
+			return errors.New("err")
+		}
+
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal(1, calls)
+}
+
+func (slf *OnCommit) TestHookErrorNotRetried() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		return trm.OnCommit(ctx, func(context.Context) error { return errors.New("publish") })
+	})
+	slf.Require().EqualError(err, "on commit: publish")
+}
+
+func (slf *OnCommit) TestLogOnlyPolicy() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	var logged []error
+	impl := trm.New(db, &mockWithTx{}, trm.WithOnCommitErrorPolicy(trm.LogOnly, func(_ context.Context, err error) {
+		logged = append(logged, err)
+	}))
+
+	err = impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		return trm.OnCommit(ctx, func(context.Context) error { return errors.New("publish") })
+	})
+	slf.Require().NoError(err)
+	slf.Require().Len(logged, 1)
+	slf.Require().EqualError(logged[0], "on commit: publish")
+	slf.Require().NoError(mock.ExpectationsWereMet())
+}
+
+func (slf *OnCommit) TestOutsideTransaction() {
+	err := trm.OnCommit(slf.ctx, func(context.Context) error { return nil })
+	slf.Require().ErrorIs(err, trm.ErrNoTransaction)
+}
+
+func TestOnCommit(t *testing.T) {
+	suite.Run(t, new(OnCommit))
+}
@@ -0,0 +1,59 @@
+package trm
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Options is a snapshot of the options a transactor was built with by New. Context and call options,
+// e.g. WithContextOptions or Timeout, are not reflected.
+type Options struct {
+	// Isolation and ReadOnly are those of WithTxOptions, the zero values without it.
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+	// Retry reports whether rolled back attempts are retried: a WithRollbackDecider is set and
+	// WithRetryDefault did not disable it.
+	Retry bool
+	// RetryBeginFailures reports whether OnBeginFailure is set.
+	RetryBeginFailures bool
+	DefaultTimeout     time.Duration
+	MaxTxDuration      time.Duration
+	MaxRowsAffected    int64
+	LazyBegin          bool
+	ForbidNesting      bool
+	PanicOnCommitError bool
+	NormalizeErrors    bool
+	OnCommitError      OnCommitErrorPolicy
+	// Replica reports whether WithReplica is set, Sharded whether WithShardResolver is.
+	Replica bool
+	Sharded bool
+}
+
+// Options returns the effective configuration of the transactor, e.g. to check in an integration
+// test that the dependency injection wiring built the reporting transactor read-only serializable.
+// It has no effect on the transactor.
+func (slf *impl[T]) Options() Options {
+	c := slf.cfg
+
+	opts := Options{
+		Retry:              c.rollbackDecider != nil && !c.retryDisabled,
+		RetryBeginFailures: c.onBeginFailure != nil,
+		DefaultTimeout:     c.defaultTimeout,
+		MaxTxDuration:      c.maxTxDuration,
+		MaxRowsAffected:    c.maxRowsAffected,
+		LazyBegin:          c.lazyBegin,
+		ForbidNesting:      c.forbidNesting,
+		PanicOnCommitError: c.panicOnCommit,
+		NormalizeErrors:    c.normalizeErrors,
+		OnCommitError:      c.onCommitPolicy,
+		Replica:            c.replica != nil,
+		Sharded:            c.resolveShard != nil,
+	}
+
+	if c.txOpts != nil {
+		opts.Isolation = c.txOpts.Isolation
+		opts.ReadOnly = c.txOpts.ReadOnly
+	}
+
+	return opts
+}
@@ -0,0 +1,54 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type Options struct {
+	suite.Suite
+}
+
+func (slf *Options) TestDefaults() {
+	impl := trm.New(&sql.DB{}, &mockWithTx{})
+
+	slf.Equal(trm.Options{}, impl.Options())
+}
+
+func (slf *Options) TestConfigured() {
+	impl := trm.New(&sql.DB{}, &mockWithTx{},
+		trm.WithTxOptions(&sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}),
+		trm.WithRollbackDecider(func(context.Context, int, error) bool { return false }),
+		trm.WithDefaultTimeout(time.Second),
+		trm.WithMaxRowsAffected(1000),
+		trm.WithReplica(&sql.DB{}),
+	)
+
+	slf.Equal(trm.Options{
+		Isolation:       sql.LevelSerializable,
+		ReadOnly:        true,
+		Retry:           true,
+		DefaultTimeout:  time.Second,
+		MaxRowsAffected: 1000,
+		Replica:         true,
+	}, impl.Options())
+}
+
+func (slf *Options) TestRetryDisabled() {
+	impl := trm.New(&sql.DB{}, &mockWithTx{},
+		trm.WithRollbackDecider(func(context.Context, int, error) bool { return true }),
+		trm.WithRetryDefault(false),
+	)
+
+	slf.False(impl.Options().Retry)
+}
+
+func TestOptions(t *testing.T) {
+	suite.Run(t, new(Options))
+}
@@ -0,0 +1,52 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+)
+
+// intent is what a transaction is declared to do, see InTxRead and InTxWrite.
+type intent int
+
+const (
+	intentNone intent = iota
+	intentRead
+	intentWrite
+)
+
+// WithReplica routes InTxRead transactions to db, e.g. a streaming replica of the primary.
+// Without it they run on the primary like any other transaction.
+func WithReplica(db *sql.DB) Option {
+	return func(c *config) {
+		c.replica = db
+	}
+}
+
+// InTxRead is InTx for operations that only read: the transaction runs on the WithReplica database
+// and is read-only, whatever the transaction options resolve to otherwise. The isolation level
+// is kept. Declaring the intent once keeps routing and options from disagreeing.
+func (slf *impl[T]) InTxRead(ctx context.Context, fn func(repo T) error) error {
+	if slf.cfg.replica == nil {
+		return slf.inTxIntent(ctx, intentRead, fn)
+	}
+
+	_, err := slf.runOn(ctx, slf.cfg.replica, &call{intent: intentRead}, func(_ context.Context, repo T) error {
+		return fn(repo)
+	})
+
+	return err
+}
+
+// InTxWrite is InTx for operations that write: the transaction runs on the primary database,
+// picked like for InTx, and is read-write, whatever the transaction options resolve to otherwise.
+func (slf *impl[T]) InTxWrite(ctx context.Context, fn func(repo T) error) error {
+	return slf.inTxIntent(ctx, intentWrite, fn)
+}
+
+func (slf *impl[T]) inTxIntent(ctx context.Context, in intent, fn func(repo T) error) error {
+	_, err := slf.run(ctx, &call{intent: in}, func(_ context.Context, repo T) error {
+		return fn(repo)
+	})
+
+	return err
+}
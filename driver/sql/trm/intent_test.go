@@ -0,0 +1,84 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type Intent struct {
+	suite.Suite
+
+	ctx     context.Context
+	primary sqlmock.Sqlmock
+	replica sqlmock.Sqlmock
+	impl    *trm.Impl[*mockWithTx]
+	opts    *sql.TxOptions
+}
+
+func (slf *Intent) SetupTest() {
+	primaryDB, primary, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	replicaDB, replica, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.primary = primary
+	slf.replica = replica
+	slf.opts = nil
+	slf.impl = trm.New(primaryDB, &mockWithTx{},
+		trm.WithReplica(replicaDB),
+		trm.WithTxOptions(&sql.TxOptions{Isolation: sql.LevelRepeatableRead}),
+		trm.WithEventSink(func(_ context.Context, e trm.Event) {
+			if e.Kind == trm.EventBegin {
+				slf.opts = e.TxOptions
+			}
+		}),
+	)
+}
+
+func (slf *Intent) TearDownTest() {
+	slf.NoError(slf.primary.ExpectationsWereMet())
+	slf.NoError(slf.replica.ExpectationsWereMet())
+}
+
+func (slf *Intent) TestRead() {
+	slf.replica.ExpectBegin()
+	slf.replica.ExpectCommit()
+
+	err := slf.impl.InTxRead(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+	slf.Equal(&sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, slf.opts)
+}
+
+func (slf *Intent) TestWrite() {
+	slf.primary.ExpectBegin()
+	slf.primary.ExpectCommit()
+
+	ctx := trm.WithContextOptions(slf.ctx, &sql.TxOptions{ReadOnly: true})
+	err := slf.impl.InTxWrite(ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+	slf.Equal(&sql.TxOptions{}, slf.opts)
+}
+
+func (slf *Intent) TestReadWithoutReplica() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	err = trm.New(db, &mockWithTx{}).InTxRead(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+	slf.NoError(mock.ExpectationsWereMet())
+}
+
+func TestIntent(t *testing.T) {
+	suite.Run(t, new(Intent))
+}
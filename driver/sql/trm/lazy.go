@@ -0,0 +1,115 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// WithLazyBegin defers BeginTx until the callback executes its first statement, so no connection
+// is held while the callback does non-database work. A callback that never touches the database
+// opens no transaction: commit is a no-op, OnCommit hooks still run and no begin or commit
+// events are reported.
+//
+// Options that act right after begin (statement timeouts, advisory locks, WithStartTimeFromDB)
+// run lazily as well, before that first statement, and TxStartTime is the zero time until then.
+// A begin failure is returned by the first statement and by InTx, wrapped in *BeginError, and
+// is retried by OnBeginFailure. Because the callback has already run, that retry runs it again.
+func WithLazyBegin() Option {
+	return func(c *config) {
+		c.lazyBegin = true
+	}
+}
+
+// lazyBegin begins the transaction of st on first use.
+type lazyBegin struct {
+	once  sync.Once
+	begin func() error
+	err   error
+	db    beginner
+}
+
+// rawTx returns the transaction of st, beginning it first with WithLazyBegin.
+func (slf *txState) rawTx() (*sql.Tx, error) {
+	if slf.lazy == nil {
+		return slf.tx, nil
+	}
+
+	slf.lazy.once.Do(func() {
+		slf.lazy.err = slf.lazy.begin()
+	})
+	if slf.lazy.err != nil {
+		return nil, slf.lazy.err
+	}
+
+	return slf.tx, nil
+}
+
+func (slf *txState) beginErr() error {
+	if slf == nil || slf.lazy == nil {
+		return nil
+	}
+
+	return slf.lazy.err
+}
+
+type lazyTx struct {
+	st *txState
+}
+
+func (slf *lazyTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	tx, err := slf.st.rawTx()
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.ExecContext(ctx, query, args...)
+}
+
+func (slf *lazyTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	tx, err := slf.st.rawTx()
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.PrepareContext(ctx, query)
+}
+
+func (slf *lazyTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	tx, err := slf.st.rawTx()
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.QueryContext(ctx, query, args...)
+}
+
+func (slf *lazyTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	tx, err := slf.st.rawTx()
+	if err != nil {
+		// *sql.Row cannot carry an arbitrary error: a cancelled context makes the database
+		// fail the row without acquiring a connection, and InTx returns the begin error.
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		return slf.st.lazy.db.QueryRowContext(cancelled, query, args...)
+	}
+
+	return tx.QueryRowContext(ctx, query, args...)
+}
+
+func (slf *lazyTx) Commit() error {
+	if slf.st.tx == nil {
+		return nil
+	}
+
+	return slf.st.tx.Commit()
+}
+
+func (slf *lazyTx) Rollback() error {
+	if slf.st.tx == nil {
+		return nil
+	}
+
+	return slf.st.tx.Rollback()
+}
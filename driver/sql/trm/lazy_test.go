@@ -0,0 +1,138 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type LazyBegin struct {
+	suite.Suite
+
+	ctx    context.Context
+	mock   sqlmock.Sqlmock
+	events []trm.EventKind
+	impl   *trm.Impl[*txRepo]
+}
+
+func (slf *LazyBegin) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.events = nil
+	slf.impl = trm.New(db, &txRepo{},
+		trm.WithLazyBegin(),
+		trm.WithEventSink(func(_ context.Context, e trm.Event) {
+			slf.events = append(slf.events, e.Kind)
+		}),
+	)
+}
+
+func (slf *LazyBegin) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *LazyBegin) TestUntouched() {
+	hooked := false
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		return trm.OnCommit(ctx, func(context.Context) error {
+			hooked = true
+			return nil
+		})
+	})
+	slf.Require().NoError(err)
+	slf.True(hooked)
+	slf.Empty(slf.events)
+}
+
+func (slf *LazyBegin) TestBeginsOnFirstStatement() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectExec("UPDATE orders").WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		_, err := r.tx.ExecContext(slf.ctx, "UPDATE users SET name = ''")
+		if err != nil {
+			return err
+		}
+
+		_, err = r.tx.ExecContext(slf.ctx, "UPDATE orders SET item = ''")
+		return err
+	})
+	slf.Require().NoError(err)
+	slf.Equal([]trm.EventKind{trm.EventBegin, trm.EventCommit}, slf.events)
+}
+
+func (slf *LazyBegin) TestBeginFailure() {
+	expected := errors.New("begin")
+	slf.mock.ExpectBegin().WillReturnError(expected)
+
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		var n int
+		errRow := r.tx.QueryRowContext(slf.ctx, "SELECT 1").Scan(&n)
+		slf.Require().Error(errRow)
+
+		return nil
+	})
+
+	var errBegin *trm.BeginError
+	slf.Require().ErrorAs(err, &errBegin)
+	slf.Require().ErrorIs(err, expected)
+}
+
+func (slf *LazyBegin) TestBeginFailureInDeferred() {
+	expected := errors.New("begin")
+	slf.mock.ExpectBegin().WillReturnError(expected)
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		return trm.DeferInTx(ctx, func(r *txRepo) error {
+			var n int
+			_ = r.tx.QueryRowContext(slf.ctx, "SELECT 1").Scan(&n) // the failure is ignored
+
+			return nil
+		})
+	})
+
+	var errBegin *trm.BeginError
+	slf.Require().ErrorAs(err, &errBegin)
+	slf.Require().ErrorIs(err, expected)
+	slf.NotContains(slf.events, trm.EventCommit)
+}
+
+func (slf *LazyBegin) TestCallbackErrorAfterBeginRollsBack() {
+	expected := errors.New("callback")
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		_, err := r.tx.ExecContext(slf.ctx, "UPDATE users SET name = ''")
+		slf.Require().NoError(err)
+
+		return expected
+	})
+	slf.Require().ErrorIs(err, expected)
+	slf.Equal([]trm.EventKind{trm.EventBegin, trm.EventRollback}, slf.events)
+}
+
+func (slf *LazyBegin) TestCallbackErrorWithoutBegin() {
+	expected := errors.New("callback")
+
+	err := slf.impl.InTx(slf.ctx, func(*txRepo) error {
+		return expected
+	})
+	slf.Require().ErrorIs(err, expected)
+	slf.Empty(slf.events)
+}
+
+func TestLazyBegin(t *testing.T) {
+	suite.Run(t, new(LazyBegin))
+}
@@ -0,0 +1,59 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrLockNotAcquired = errors.New("trm: advisory lock is held by another transaction")
+
+// WithAdvisoryLock takes pg_advisory_xact_lock right after begin when key returns true,
+// waiting until the lock is free. PostgreSQL releases it at commit or rollback, so transactions
+// using the same key are serialized without a dedicated lock table. Waiting is bounded by ctx
+// and by the lock_timeout of the session.
+func WithAdvisoryLock(key func(ctx context.Context) (int64, bool)) Option {
+	return func(c *config) {
+		c.setup = append(c.setup, func(ctx context.Context, tx *sql.Tx) error {
+			k, ok := key(ctx)
+			if !ok {
+				return nil
+			}
+
+			_, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", k)
+			if err != nil {
+				return fmt.Errorf("advisory lock %d: %w", k, err)
+			}
+
+			return nil
+		})
+	}
+}
+
+// WithTryAdvisoryLock is the non-blocking variant of WithAdvisoryLock: it takes
+// pg_try_advisory_xact_lock and rolls back with ErrLockNotAcquired when the lock is held
+// by another transaction.
+func WithTryAdvisoryLock(key func(ctx context.Context) (int64, bool)) Option {
+	return func(c *config) {
+		c.setup = append(c.setup, func(ctx context.Context, tx *sql.Tx) error {
+			k, ok := key(ctx)
+			if !ok {
+				return nil
+			}
+
+			var locked bool
+
+			err := tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", k).Scan(&locked)
+			if err != nil {
+				return fmt.Errorf("advisory lock %d: %w", k, err)
+			}
+
+			if !locked {
+				return fmt.Errorf("advisory lock %d: %w", k, ErrLockNotAcquired)
+			}
+
+			return nil
+		})
+	}
+}
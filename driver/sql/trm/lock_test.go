@@ -0,0 +1,97 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type lockKey struct{}
+
+func lockKeyFrom(ctx context.Context) (int64, bool) {
+	k, ok := ctx.Value(lockKey{}).(int64)
+	return k, ok
+}
+
+type AdvisoryLock struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	wait *trm.Impl[*mockWithTx]
+	try  *trm.Impl[*mockWithTx]
+}
+
+func (slf *AdvisoryLock) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.WithValue(context.Background(), lockKey{}, int64(42))
+	slf.mock = mock
+	slf.wait = trm.New(db, &mockWithTx{}, trm.WithAdvisoryLock(lockKeyFrom))
+	slf.try = trm.New(db, &mockWithTx{}, trm.WithTryAdvisoryLock(lockKeyFrom))
+}
+
+func (slf *AdvisoryLock) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *AdvisoryLock) TestWait() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SELECT pg_advisory_xact_lock($1)").WithArgs(int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectCommit()
+
+	err := slf.wait.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func (slf *AdvisoryLock) TestNoKey() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.wait.InTx(context.Background(), func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func (slf *AdvisoryLock) TestWaitFailureRollsBack() {
+	expected := errors.New("lock timeout")
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SELECT pg_advisory_xact_lock($1)").WithArgs(int64(42)).WillReturnError(expected)
+	slf.mock.ExpectRollback()
+
+	err := slf.wait.InTx(slf.ctx, func(*mockWithTx) error {
+		slf.Fail("callback must not run")
+		return nil
+	})
+	slf.Require().ErrorIs(err, expected)
+}
+
+func (slf *AdvisoryLock) TestTryAcquired() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery("SELECT pg_try_advisory_xact_lock($1)").WithArgs(int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+	slf.mock.ExpectCommit()
+
+	err := slf.try.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func (slf *AdvisoryLock) TestTryHeld() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery("SELECT pg_try_advisory_xact_lock($1)").WithArgs(int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(false))
+	slf.mock.ExpectRollback()
+
+	err := slf.try.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().ErrorIs(err, trm.ErrLockNotAcquired)
+}
+
+func TestAdvisoryLock(t *testing.T) {
+	suite.Run(t, new(AdvisoryLock))
+}
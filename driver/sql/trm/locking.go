@@ -0,0 +1,58 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+)
+
+// InTxLocking is InTx for a transaction declared to touch tables: right after begin, before fn
+// and the rest of the transaction, it takes pg_advisory_xact_lock(hashtext(table)) for each table
+// in the order of the lock keys, without duplicate keys. As every InTxLocking call locks in the
+// same global order, transactions over overlapping tables queue up instead of deadlocking.
+//
+// The keys are computed by the server with one SELECT hashtext($1) per table first, so that
+// the order and the deduplication follow the keys actually locked: distinct names sharing a
+// hash take the lock once and only serialize more.
+//
+// The locks are advisory: they only order transactions that also use InTxLocking, and the table
+// rows stay readable and writable by everyone else. PostgreSQL releases the locks at commit or
+// rollback.
+func (slf *impl[T]) InTxLocking(ctx context.Context, tables []string, fn func(repo T) error) error {
+	tables = slices.Clone(tables)
+	slices.Sort(tables)
+
+	_, err := slf.run(ctx, &call{lockTables: slices.Compact(tables)}, func(_ context.Context, repo T) error {
+		return fn(repo)
+	})
+
+	return err
+}
+
+// lockTables takes the advisory locks of InTxLocking, in the order of their keys.
+func lockTables(ctx context.Context, tx *sql.Tx, tables []string) error {
+	keys := make([]int64, 0, len(tables))
+
+	for _, table := range tables {
+		var key int64
+
+		err := tx.QueryRowContext(ctx, "SELECT hashtext($1)", table).Scan(&key)
+		if err != nil {
+			return fmt.Errorf("lock table %s: %w", table, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range slices.Compact(keys) {
+		_, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", key)
+		if err != nil {
+			return fmt.Errorf("lock key %d: %w", key, err)
+		}
+	}
+
+	return nil
+}
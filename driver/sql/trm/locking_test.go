@@ -0,0 +1,91 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type InTxLocking struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+}
+
+func (slf *InTxLocking) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{})
+}
+
+func (slf *InTxLocking) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *InTxLocking) expectKey(table string, key int64) {
+	slf.mock.ExpectQuery("SELECT hashtext($1)").
+		WithArgs(table).
+		WillReturnRows(sqlmock.NewRows([]string{"hashtext"}).AddRow(key))
+}
+
+func (slf *InTxLocking) expectLock(key int64) *sqlmock.ExpectedExec {
+	return slf.mock.ExpectExec("SELECT pg_advisory_xact_lock($1)").WithArgs(key)
+}
+
+func (slf *InTxLocking) TestLocksInKeyOrder() {
+	slf.mock.ExpectBegin()
+	slf.expectKey("accounts", 30)
+	slf.expectKey("orders", -10)
+	slf.expectKey("users", 20)
+	slf.expectLock(-10).WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.expectLock(20).WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.expectLock(30).WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectCommit()
+
+	tables := []string{"users", "orders", "accounts", "orders"}
+	err := slf.impl.InTxLocking(slf.ctx, tables, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+	slf.Equal([]string{"users", "orders", "accounts", "orders"}, tables)
+}
+
+func (slf *InTxLocking) TestSharedKeyLockedOnce() {
+	slf.mock.ExpectBegin()
+	slf.expectKey("accounts", 7)
+	slf.expectKey("orders", 7)
+	slf.expectLock(7).WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxLocking(slf.ctx, []string{"orders", "accounts"}, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func (slf *InTxLocking) TestLockFailureRollsBack() {
+	errTimeout := errors.New("canceling statement due to lock timeout")
+
+	slf.mock.ExpectBegin()
+	slf.expectKey("accounts", 1)
+	slf.expectLock(1).WillReturnError(errTimeout)
+	slf.mock.ExpectRollback()
+
+	called := false
+	err := slf.impl.InTxLocking(slf.ctx, []string{"accounts"}, func(*mockWithTx) error {
+		called = true
+		return nil
+	})
+	slf.Require().ErrorIs(err, errTimeout)
+	slf.False(called)
+}
+
+func TestInTxLocking(t *testing.T) {
+	suite.Run(t, new(InTxLocking))
+}
@@ -0,0 +1,97 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// WithLockWaitSampling reports how long every transaction was blocked by locks held by other
+// transactions in Event.LockWait of its commit and rollback events, telling lock contention
+// apart from slow queries.
+//
+// Right after begin the backend of the transaction is looked up with pg_backend_pid(); then,
+// until the transaction ends, pg_blocking_pids() of that backend is sampled every interval on
+// another pooled connection. LockWait is the number of samples that found it blocked times
+// interval, so waits shorter than interval may go unnoticed. PostgreSQL only; transactions of
+// a Session are not sampled, as their connection cannot be shared.
+func WithLockWaitSampling(interval time.Duration) Option {
+	return func(c *config) {
+		c.lockWait = interval
+	}
+}
+
+// startLockWait starts sampling the lock waits of the transaction of st, begun on db.
+func (slf *config) startLockWait(ctx context.Context, db beginner, st *txState) error {
+	pool, ok := db.(*sql.DB)
+	if slf.lockWait <= 0 || !ok || !st.sampled {
+		return nil
+	}
+
+	var pid int
+
+	err := st.tx.QueryRowContext(ctx, "SELECT pg_backend_pid()").Scan(&pid)
+	if err != nil {
+		return fmt.Errorf("sample lock wait: %w", err)
+	}
+
+	st.lockWait = newLockSampler(ctx, pool, pid, slf.lockWait)
+
+	return nil
+}
+
+type lockSampler struct {
+	interval time.Duration
+	// stop cancels the sample in flight too, which may wait for a free connection of the pool.
+	stop context.CancelFunc
+	done chan struct{}
+	// blocked counts the samples that found the backend blocked, read once done is closed.
+	blocked int
+}
+
+func newLockSampler(ctx context.Context, db *sql.DB, pid int, interval time.Duration) *lockSampler {
+	ctx, stop := context.WithCancel(context.WithoutCancel(ctx))
+	s := &lockSampler{
+		interval: interval,
+		stop:     stop,
+		done:     make(chan struct{}),
+	}
+
+	go s.run(ctx, db, pid)
+
+	return s
+}
+
+func (slf *lockSampler) run(ctx context.Context, db *sql.DB, pid int) {
+	defer close(slf.done)
+
+	ticker := time.NewTicker(slf.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			var blockers int
+
+			err := db.QueryRowContext(ctx, "SELECT cardinality(pg_blocking_pids($1))", pid).Scan(&blockers)
+			if err == nil && blockers > 0 {
+				slf.blocked++
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// finish stops sampling and returns the time the transaction was found blocked.
+func (slf *lockSampler) finish() time.Duration {
+	if slf == nil {
+		return 0
+	}
+
+	slf.stop()
+	<-slf.done
+
+	return time.Duration(slf.blocked) * slf.interval
+}
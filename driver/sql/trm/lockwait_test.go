@@ -0,0 +1,122 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type LockWait struct {
+	suite.Suite
+}
+
+func (slf *LockWait) run(
+	fn func(t *testing.T, mock sqlmock.Sqlmock, impl *trm.Impl[*mockWithTx], events *[]trm.Event),
+) {
+	synctest.Test(slf.T(), func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+
+		defer func() { _ = db.Close() }()
+
+		// Samples run concurrently with the statements of the transaction.
+		mock.MatchExpectationsInOrder(false)
+
+		var events []trm.Event
+		impl := trm.New(db, &mockWithTx{},
+			trm.WithLockWaitSampling(10*time.Millisecond),
+			trm.WithEventSink(func(_ context.Context, e trm.Event) {
+				events = append(events, e)
+			}),
+		)
+
+		fn(t, mock, impl, &events)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func (slf *LockWait) expectSamples(mock sqlmock.Sqlmock, blockers ...int) {
+	for _, n := range blockers {
+		mock.ExpectQuery("SELECT cardinality(pg_blocking_pids($1))").
+			WithArgs(42).
+			WillReturnRows(sqlmock.NewRows([]string{"cardinality"}).AddRow(n))
+	}
+}
+
+func (slf *LockWait) TestCommit() {
+	slf.run(func(t *testing.T, mock sqlmock.Sqlmock, impl *trm.Impl[*mockWithTx], events *[]trm.Event) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT pg_backend_pid()").WillReturnRows(sqlmock.NewRows([]string{"pid"}).AddRow(42))
+		slf.expectSamples(mock, 1, 2, 0)
+		mock.ExpectCommit()
+
+		err := impl.InTx(context.Background(), func(*mockWithTx) error {
+			time.Sleep(35 * time.Millisecond)
+			return nil
+		})
+		require.NoError(t, err)
+
+		require.Len(t, *events, 2)
+		require.Equal(t, trm.EventCommit, (*events)[1].Kind)
+		require.Equal(t, 20*time.Millisecond, (*events)[1].LockWait)
+	})
+}
+
+func (slf *LockWait) TestRollback() {
+	slf.run(func(t *testing.T, mock sqlmock.Sqlmock, impl *trm.Impl[*mockWithTx], events *[]trm.Event) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT pg_backend_pid()").WillReturnRows(sqlmock.NewRows([]string{"pid"}).AddRow(42))
+		slf.expectSamples(mock, 1)
+		mock.ExpectRollback()
+
+		errFailed := errors.New("failed")
+		err := impl.InTx(context.Background(), func(*mockWithTx) error {
+			time.Sleep(15 * time.Millisecond)
+			return errFailed
+		})
+		require.ErrorIs(t, err, errFailed)
+
+		require.Len(t, *events, 2)
+		require.Equal(t, trm.EventRollback, (*events)[1].Kind)
+		require.Equal(t, 10*time.Millisecond, (*events)[1].LockWait)
+	})
+}
+
+func (slf *LockWait) TestNotBlocked() {
+	slf.run(func(t *testing.T, mock sqlmock.Sqlmock, impl *trm.Impl[*mockWithTx], events *[]trm.Event) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT pg_backend_pid()").WillReturnRows(sqlmock.NewRows([]string{"pid"}).AddRow(42))
+		mock.ExpectCommit()
+
+		err := impl.InTx(context.Background(), func(*mockWithTx) error { return nil })
+		require.NoError(t, err)
+
+		require.Len(t, *events, 2)
+		require.Zero(t, (*events)[1].LockWait)
+	})
+}
+
+func (slf *LockWait) TestBackendLookupFails() {
+	slf.run(func(t *testing.T, mock sqlmock.Sqlmock, impl *trm.Impl[*mockWithTx], _ *[]trm.Event) {
+		errUnsupported := errors.New("function pg_backend_pid() does not exist")
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT pg_backend_pid()").WillReturnError(errUnsupported)
+		mock.ExpectRollback()
+
+		err := impl.InTx(context.Background(), func(*mockWithTx) error { return nil })
+		require.ErrorIs(t, err, errUnsupported)
+	})
+}
+
+func TestLockWait(t *testing.T) {
+	suite.Run(t, new(LockWait))
+}
@@ -0,0 +1,26 @@
+package trm
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LoggerFromContext returns slog.Default with the tx_id attribute of the transaction carried by ctx,
+// see TxID, and the op attribute of its operation name, see WithOpName, so log lines of repositories
+// are correlated without threading a logger through every method. Outside a transaction it returns
+// slog.Default as is; op is left out when ctx carries no operation name.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+
+	st := stateFrom(ctx)
+	if st == nil {
+		return logger
+	}
+
+	logger = logger.With(slog.String("tx_id", st.id()))
+	if op := OpName(ctx); op != "" {
+		logger = logger.With(slog.String("op", op))
+	}
+
+	return logger
+}
@@ -0,0 +1,66 @@
+package trm_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type LoggerFromContext struct {
+	suite.Suite
+
+	buf      *bytes.Buffer
+	previous *slog.Logger
+}
+
+func (slf *LoggerFromContext) SetupTest() {
+	slf.buf = &bytes.Buffer{}
+	slf.previous = slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(slf.buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	})))
+}
+
+func (slf *LoggerFromContext) TearDownTest() {
+	slog.SetDefault(slf.previous)
+}
+
+func (slf *LoggerFromContext) TestInTransaction() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	impl := trm.New(db, &mockWithTx{}, trm.WithIDGenerator(func() string { return "tx1" }))
+
+	ctx := trm.WithOpName(context.Background(), "CreateOrder")
+	err = impl.InTxCtx(ctx, func(ctx context.Context, _ *mockWithTx) error {
+		trm.LoggerFromContext(ctx).Info("created")
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Require().NoError(mock.ExpectationsWereMet())
+	slf.Equal("level=INFO msg=created tx_id=tx1 op=CreateOrder\n", slf.buf.String())
+}
+
+func (slf *LoggerFromContext) TestOutsideTransaction() {
+	trm.LoggerFromContext(trm.WithOpName(context.Background(), "CreateOrder")).Info("created")
+	slf.Equal("level=INFO msg=created\n", slf.buf.String())
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	suite.Run(t, new(LoggerFromContext))
+}
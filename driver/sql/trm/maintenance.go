@@ -0,0 +1,31 @@
+package trm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrMaintenanceMode = errors.New("trm: writes are disabled for maintenance")
+
+// SetMaintenanceMode turns maintenance mode on or off at runtime, e.g. from an admin endpoint during
+// a migration freeze. While it is on, InTx calls whose transaction options do not resolve to read-only
+// (see InTxRead, WithContextOptions) fail with ErrMaintenanceMode before beginning, and read-only
+// ones proceed. It is safe for concurrent use and affects calls starting after it returns; the mode
+// belongs to the transactor, so every process of a cluster has to be switched.
+func (slf *impl[T]) SetMaintenanceMode(enabled bool) {
+	slf.maintenance.Store(enabled)
+}
+
+func (slf *impl[T]) checkMaintenance(ctx context.Context, c *call) error {
+	if !slf.maintenance.Load() {
+		return nil
+	}
+
+	opts := slf.cfg.txOptions(ctx, c)
+	if opts != nil && opts.ReadOnly {
+		return nil
+	}
+
+	return fmt.Errorf("begin tx: %w", ErrMaintenanceMode)
+}
@@ -0,0 +1,73 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type MaintenanceMode struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+}
+
+func (slf *MaintenanceMode) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{})
+	slf.impl.SetMaintenanceMode(true)
+}
+
+func (slf *MaintenanceMode) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *MaintenanceMode) TestRejectsWrites() {
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error {
+		slf.Fail("callback must not run")
+		return nil
+	})
+	slf.Require().ErrorIs(err, trm.ErrMaintenanceMode)
+
+	err = slf.impl.InTxWrite(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().ErrorIs(err, trm.ErrMaintenanceMode)
+}
+
+func (slf *MaintenanceMode) TestAllowsReads() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxRead(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+
+	ctx := trm.WithContextOptions(slf.ctx, &sql.TxOptions{ReadOnly: true})
+	err = slf.impl.InTx(ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func (slf *MaintenanceMode) TestDisabled() {
+	slf.impl.SetMaintenanceMode(false)
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func TestMaintenanceMode(t *testing.T) {
+	suite.Run(t, new(MaintenanceMode))
+}
@@ -0,0 +1,43 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// WithMaxTxDuration bounds every transaction attempt to d, both in Go and on the server.
+//
+// The context of the transaction gets a deadline of d from begin, so a runaway callback fails
+// its next statement and the transaction rolls back; unless WithCommitContext detaches it,
+// a commit started after the deadline fails as well. OnCommit hooks run with the original context.
+//
+// Right after begin SET LOCAL idle_in_transaction_session_timeout is issued, so PostgreSQL
+// terminates the session of a transaction idling for longer than d while holding locks, e.g.
+// when the process is stuck and cannot cancel anything itself. The setting exists in PostgreSQL
+// 9.6 and later only; if it cannot be set, the transaction is rolled back, as a failed statement
+// aborts a PostgreSQL transaction. Other backends (MySQL, SQLite) reject it on every begin and
+// should bound their transactions with WithDefaultTimeout or the Timeout call option instead.
+func WithMaxTxDuration(d time.Duration) Option {
+	return func(c *config) {
+		c.maxTxDuration = d
+		c.setup = append(c.setup, func(ctx context.Context, tx *sql.Tx) error {
+			err := setLocalTimeout(ctx, tx, "idle_in_transaction_session_timeout", d)
+			if err != nil {
+				return fmt.Errorf("set idle in transaction timeout: %w", err)
+			}
+
+			return nil
+		})
+	}
+}
+
+// txDeadline returns the context a transaction attempt runs with.
+func (slf *config) txDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if slf.maxTxDuration <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, slf.maxTxDuration)
+}
@@ -0,0 +1,84 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type MaxTxDuration struct {
+	suite.Suite
+
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+}
+
+func (slf *MaxTxDuration) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithMaxTxDuration(20*time.Millisecond))
+}
+
+func (slf *MaxTxDuration) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *MaxTxDuration) TestSetsBoth() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SET LOCAL idle_in_transaction_session_timeout = 20").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit()
+
+	var hookHasDeadline bool
+	err := slf.impl.InTxCtx(context.Background(), func(ctx context.Context, _ *mockWithTx) error {
+		deadline, ok := ctx.Deadline()
+		slf.Require().True(ok)
+		slf.WithinDuration(time.Now().Add(20*time.Millisecond), deadline, 20*time.Millisecond)
+
+		return trm.OnCommit(ctx, func(ctx context.Context) error {
+			_, hookHasDeadline = ctx.Deadline()
+			return nil
+		})
+	})
+	slf.Require().NoError(err)
+	slf.False(hookHasDeadline)
+}
+
+func (slf *MaxTxDuration) TestServerSideUnsupported() {
+	errSyntax := errors.New("syntax error")
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SET LOCAL idle_in_transaction_session_timeout = 20").WillReturnError(errSyntax)
+	slf.mock.ExpectRollback()
+
+	called := false
+	err := slf.impl.InTx(context.Background(), func(*mockWithTx) error {
+		called = true
+		return nil
+	})
+	slf.Require().ErrorIs(err, errSyntax)
+	slf.False(called)
+}
+
+func (slf *MaxTxDuration) TestRunawayCallback() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SET LOCAL idle_in_transaction_session_timeout = 20").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTxCtx(context.Background(), func(ctx context.Context, _ *mockWithTx) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	slf.Require().ErrorIs(err, context.DeadlineExceeded)
+}
+
+func TestMaxTxDuration(t *testing.T) {
+	suite.Run(t, new(MaxTxDuration))
+}
@@ -0,0 +1,10 @@
+package trm
+
+// TxMeta describes the final attempt of a transaction run by InTxMeta.
+type TxMeta struct {
+	// RowsAffected is the sum of RowsAffected of every ExecContext executed through
+	// the transaction. It is collected only with WithRowsAffected.
+	RowsAffected int64
+	// RollbackReason tells why the final attempt was rolled back, RollbackNone if it committed.
+	RollbackReason RollbackReason
+}
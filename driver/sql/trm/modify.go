@@ -0,0 +1,58 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+)
+
+// WithModifyTracking records whether the transaction modified data, see DidModify.
+//
+// An ExecContext counts as a modification when it reports a non-zero RowsAffected, or when
+// RowsAffected is unknown (unsupported by the driver or statement): for cache invalidation
+// a spurious modification is cheaper than a missed one. For the same reason preparing
+// a statement counts as a modification. Rows changed by QueryContext and QueryRowContext,
+// e.g. INSERT ... RETURNING, are not seen.
+func WithModifyTracking() Option {
+	return func(c *config) {
+		c.wrappers = append(c.wrappers, func(st *txState, tx Transaction) Transaction {
+			return &modifyTx{Transaction: tx, st: st}
+		})
+	}
+}
+
+// DidModify reports whether the transaction carried by ctx modified data so far.
+// It is meant for OnCommit hooks skipping work after read-only transactions and always
+// returns false without WithModifyTracking or outside a transaction.
+func DidModify(ctx context.Context) bool {
+	st := stateFrom(ctx)
+	if st == nil {
+		return false
+	}
+
+	return st.modified.Load()
+}
+
+type modifyTx struct {
+	Transaction
+
+	st *txState
+}
+
+func (slf *modifyTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	res, err := slf.Transaction.ExecContext(ctx, query, args...)
+	if err != nil {
+		return res, err
+	}
+
+	n, errRows := res.RowsAffected()
+	if errRows != nil || n != 0 {
+		slf.st.modified.Store(true)
+	}
+
+	return res, nil
+}
+
+func (slf *modifyTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	slf.st.modified.Store(true)
+	return slf.Transaction.PrepareContext(ctx, query)
+}
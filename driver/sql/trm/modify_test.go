@@ -0,0 +1,99 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type DidModify struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *DidModify) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &txRepo{}, trm.WithModifyTracking())
+}
+
+func (slf *DidModify) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *DidModify) run(exec func(ctx context.Context, r *txRepo) error) bool {
+	var modified bool
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, r *txRepo) error {
+		err := trm.OnCommit(ctx, func(ctx context.Context) error {
+			modified = trm.DidModify(ctx)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return exec(ctx, r)
+	})
+	slf.Require().NoError(err)
+
+	return modified
+}
+
+func (slf *DidModify) TestReadOnly() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+	slf.mock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit()
+
+	slf.False(slf.run(func(ctx context.Context, r *txRepo) error {
+		var n int
+		err := r.tx.QueryRowContext(ctx, "SELECT 1").Scan(&n)
+		if err != nil {
+			return err
+		}
+
+		_, err = r.tx.ExecContext(ctx, "UPDATE users SET name = ''")
+		return err
+	}))
+}
+
+func (slf *DidModify) TestWrite() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 2))
+	slf.mock.ExpectCommit()
+
+	slf.True(slf.run(func(ctx context.Context, r *txRepo) error {
+		_, err := r.tx.ExecContext(ctx, "UPDATE users SET name = ''")
+		return err
+	}))
+}
+
+func (slf *DidModify) TestUnknownRowsAffected() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewErrorResult(errors.New("unknown")))
+	slf.mock.ExpectCommit()
+
+	slf.True(slf.run(func(ctx context.Context, r *txRepo) error {
+		_, err := r.tx.ExecContext(ctx, "UPDATE users SET name = ''")
+		return err
+	}))
+}
+
+func (slf *DidModify) TestOutsideTransaction() {
+	slf.False(trm.DidModify(slf.ctx))
+}
+
+func TestDidModify(t *testing.T) {
+	suite.Run(t, new(DidModify))
+}
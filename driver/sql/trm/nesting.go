@@ -0,0 +1,24 @@
+package trm
+
+import "fmt"
+
+// WithForbidNesting makes InTx fail with ErrNestedTx when it is called with a context that already
+// carries an open transaction of the same transactor, instead of silently beginning a second,
+// independent transaction that breaks the atomicity the caller assumes.
+//
+// The transaction is known only to contexts derived from the one passed to InTxCtx callbacks
+// and DeferInTx functions; a nested call made with the outer context cannot be detected.
+// OnCommit hooks run after commit and may start new transactions.
+func WithForbidNesting() Option {
+	return func(c *config) {
+		c.forbidNesting = true
+	}
+}
+
+func (slf *impl[T]) checkNesting(st *txState) error {
+	if !slf.cfg.forbidNesting || st == nil || st.committed || st.binder != binder(slf) {
+		return nil
+	}
+
+	return fmt.Errorf("begin tx: %w", ErrNestedTx)
+}
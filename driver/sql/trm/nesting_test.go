@@ -0,0 +1,77 @@
+package trm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type ForbidNesting struct {
+	suite.Suite
+
+	ctx   context.Context
+	mock  sqlmock.Sqlmock
+	impl  *trm.Impl[*txRepo]
+	other *trm.Impl[*txRepo]
+}
+
+func (slf *ForbidNesting) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &txRepo{}, trm.WithForbidNesting())
+	slf.other = trm.New(db, &txRepo{})
+}
+
+func (slf *ForbidNesting) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *ForbidNesting) TestNestedFails() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		return slf.impl.InTx(ctx, func(*txRepo) error {
+			slf.Fail("nested callback must not run")
+			return nil
+		})
+	})
+	slf.Require().ErrorIs(err, trm.ErrNestedTx)
+}
+
+func (slf *ForbidNesting) TestOtherTransactorAllowed() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		return slf.other.InTx(ctx, func(*txRepo) error { return nil })
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *ForbidNesting) TestOnCommitHookAllowed() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		return trm.OnCommit(ctx, func(ctx context.Context) error {
+			return slf.impl.InTx(ctx, func(*txRepo) error { return nil })
+		})
+	})
+	slf.Require().NoError(err)
+}
+
+func TestForbidNesting(t *testing.T) {
+	suite.Run(t, new(ForbidNesting))
+}
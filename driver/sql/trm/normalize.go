@@ -0,0 +1,116 @@
+package trm
+
+import (
+	"errors"
+	"reflect"
+)
+
+// DBError is a database error reported the same way whatever the driver, see NormalizeError.
+type DBError struct {
+	// Code is the SQLSTATE, e.g. "23505" for a unique violation.
+	Code       string
+	Message    string
+	Constraint string
+	Table      string
+	Detail     string
+	// Err is the normalized error, the driver error or an error wrapping it.
+	Err error
+}
+
+func (e *DBError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DBError) Unwrap() error {
+	return e.Err
+}
+
+// SQLState returns Code, so SQLState and ClassifySQLState understand DBError.
+func (e *DBError) SQLState() string {
+	return e.Code
+}
+
+// WithNormalizedErrors makes InTx return errors carrying a database error as a *DBError wrapping
+// them, so callers match errors.As(err, &dbErr) and switch on dbErr.Code or dbErr.Constraint
+// regardless of the driver. Other errors are returned as is. The error passed to WithErrorMapper
+// is already normalized.
+func WithNormalizedErrors() Option {
+	return func(c *config) {
+		c.normalizeErrors = true
+	}
+}
+
+// NormalizeError returns err as a *DBError when it carries a database error, or nil otherwise.
+// A DBError already in the chain of err is returned as is.
+//
+// Like SQLState, it understands lib/pq (*pq.Error: Code, Message, Constraint, Table, Detail), pgx
+// (*pgconn.PgError: Code, Message, ConstraintName, TableName, Detail) and go-sql-driver/mysql
+// (*mysql.MySQLError: SQLState, Message) errors without depending on the drivers; fields a driver
+// does not report are left empty.
+func NormalizeError(err error) *DBError {
+	var dbErr *DBError
+	if errors.As(err, &dbErr) {
+		return dbErr
+	}
+
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		code := SQLState(cause)
+		if code == "" {
+			return nil
+		}
+
+		if driverError(cause) {
+			v := reflect.Indirect(reflect.ValueOf(cause))
+
+			return &DBError{
+				Code:       code,
+				Message:    stringField(v, "Message"),
+				Constraint: stringField(v, "Constraint", "ConstraintName"),
+				Table:      stringField(v, "Table", "TableName"),
+				Detail:     stringField(v, "Detail"),
+				Err:        err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// driverError reports whether err itself, not an error it wraps, carries the SQLSTATE.
+func driverError(err error) bool {
+	if _, ok := err.(sqlStater); ok { //nolint:errorlint // the error itself is inspected, not its chain
+		return true
+	}
+
+	return sqlStateField(err) != ""
+}
+
+// stringField returns the first string field of the struct v among names, or "".
+func stringField(v reflect.Value, names ...string) string {
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+
+	for _, name := range names {
+		f := v.FieldByName(name)
+		if f.IsValid() && f.Kind() == reflect.String {
+			return f.String()
+		}
+	}
+
+	return ""
+}
+
+// normalize returns the error InTx returns for err with WithNormalizedErrors.
+func (slf *config) normalize(err error) error {
+	if err == nil || !slf.normalizeErrors {
+		return err
+	}
+
+	dbErr := NormalizeError(err)
+	if dbErr == nil {
+		return err
+	}
+
+	return dbErr
+}
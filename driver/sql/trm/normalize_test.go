@@ -0,0 +1,105 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+// pqError has the shape of *pq.Error.
+type pqError struct {
+	Code       string
+	Message    string
+	Detail     string
+	Table      string
+	Constraint string
+}
+
+func (e *pqError) Error() string    { return "pq: " + e.Message }
+func (e *pqError) SQLState() string { return e.Code }
+
+// PgError has the shape of *pgconn.PgError.
+type PgError struct {
+	Code           string
+	Message        string
+	Detail         string
+	TableName      string
+	ConstraintName string
+}
+
+func (e *PgError) Error() string    { return "ERROR: " + e.Message }
+func (e *PgError) SQLState() string { return e.Code }
+
+type NormalizeError struct {
+	suite.Suite
+}
+
+func (slf *NormalizeError) TestDrivers() {
+	want := trm.DBError{
+		Code:       "23505",
+		Message:    "duplicate key value",
+		Constraint: "users_email_key",
+		Table:      "users",
+		Detail:     "Key (email)=(a@b.c) already exists.",
+	}
+
+	for _, driverErr := range []error{
+		&pqError{Code: want.Code, Message: want.Message, Detail: want.Detail, Table: want.Table, Constraint: want.Constraint},
+		&PgError{
+			Code: want.Code, Message: want.Message, Detail: want.Detail,
+			TableName: want.Table, ConstraintName: want.Constraint,
+		},
+	} {
+		err := fmt.Errorf("create user: %w", driverErr)
+
+		got := trm.NormalizeError(err)
+		slf.Require().NotNil(got)
+		slf.Require().Equal(err, got.Err)
+
+		got.Err = nil
+		slf.Equal(want, *got)
+	}
+}
+
+func (slf *NormalizeError) TestFieldsNotReported() {
+	got := trm.NormalizeError(&MySQLError{Number: 1062, SQLState: [5]byte{'2', '3', '0', '0', '0'}})
+	slf.Require().NotNil(got)
+	slf.Equal("23000", got.Code)
+	slf.Empty(got.Constraint)
+}
+
+func (slf *NormalizeError) TestNotDatabaseError() {
+	slf.Nil(trm.NormalizeError(errors.New("failed")))
+	slf.Nil(trm.NormalizeError(nil))
+}
+
+func (slf *NormalizeError) TestWithNormalizedErrors() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	driverErr := &PgError{Code: "23505", ConstraintName: "users_email_key"}
+	impl := trm.New(db, &mockWithTx{}, trm.WithNormalizedErrors())
+
+	err = impl.InTx(context.Background(), func(*mockWithTx) error { return driverErr })
+	slf.Require().ErrorIs(err, driverErr)
+
+	var dbErr *trm.DBError
+	slf.Require().ErrorAs(err, &dbErr)
+	slf.Equal("23505", dbErr.Code)
+	slf.Equal("users_email_key", dbErr.Constraint)
+	slf.Equal(trm.ClassUniqueViolation, trm.ClassifySQLState(err))
+	slf.Require().NoError(mock.ExpectationsWereMet())
+}
+
+func TestNormalizeError(t *testing.T) {
+	suite.Run(t, new(NormalizeError))
+}
@@ -0,0 +1,22 @@
+package trm
+
+import (
+	"context"
+
+	"github.com/metalfm/transactor/tr"
+)
+
+// ErrMissingOpName is returned by transactors wrapped with tr.RequireOpName
+// when the context carries no operation name.
+var ErrMissingOpName = tr.ErrMissingOpName
+
+// WithOpName returns a copy of ctx carrying the operation name of the transactions started with it.
+// It is tr.WithOpName, so the name is visible to every driver and to tr.RequireOpName.
+func WithOpName(ctx context.Context, name string) context.Context {
+	return tr.WithOpName(ctx, name)
+}
+
+// OpName returns the operation name carried by ctx, or an empty string.
+func OpName(ctx context.Context) string {
+	return tr.OpName(ctx)
+}
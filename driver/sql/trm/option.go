@@ -0,0 +1,122 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type Option func(*config)
+
+type config struct {
+	txOpts          *sql.TxOptions
+	wrappers        []func(st *txState, tx Transaction) Transaction
+	setup           []func(ctx context.Context, tx *sql.Tx) error
+	rollbackDecider func(ctx context.Context, attempt int, err error) bool
+	onBeginFailure  func(ctx context.Context, err error) bool
+	panicOnCommit   bool
+	sink            func(ctx context.Context, e Event)
+	rollbackCtx     func(parent context.Context) context.Context
+	classify        func(err error) string
+	outbox          *outbox
+	resolveShard    func(ctx context.Context) (*sql.DB, error)
+	commitCtx       func(parent context.Context) (context.Context, context.CancelFunc)
+	lazyBegin       bool
+	newID           func() string
+	forbidNesting   bool
+	maxTxDuration   time.Duration
+	explain         *autoExplain
+	retryDisabled   bool
+	callerInfo      bool
+	beforeCommit    func(ctx context.Context) error
+	faults          func(op FaultOp) error
+	rowTransform    func(ctx context.Context, src any) (any, error)
+	async           *asyncPool
+	lockWait        time.Duration
+	mapError        func(ctx context.Context, err error) error
+	defaultTimeout  time.Duration
+	replica         *sql.DB
+	startupChecks   []func(ctx context.Context, db *sql.DB) error
+	determinism     func(ctx context.Context, err error)
+	onCommitPolicy  OnCommitErrorPolicy
+	onCommitLog     func(ctx context.Context, err error)
+	sampling        *sampling
+	externalTx      func(ctx context.Context) (Transaction, func() error, func() error, bool)
+	normalizeErrors bool
+	maxRowsAffected int64
+	stmtCache       bool
+}
+
+func newConfig(opts []Option) *config {
+	cfg := &config{
+		rollbackCtx: context.WithoutCancel,
+		classify:    ClassifySQLState,
+		newID:       newUUID,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// WithRollbackDecider registers a hook called after every rolled back attempt
+// (callback or commit failure) with the 1-based attempt number and the error.
+// Returning true runs the callback again in a new transaction.
+//
+// The decider is responsible for bounding the number of attempts.
+// Begin failures never reach the decider, and no retry happens once ctx is done.
+func WithRollbackDecider(decide func(ctx context.Context, attempt int, err error) bool) Option {
+	return func(c *config) {
+		c.rollbackDecider = decide
+	}
+}
+
+// OnBeginFailure registers a hook called when beginning a transaction fails,
+// e.g. during a failover. It receives the *BeginError and may refresh connections
+// or wait for a new primary; returning true begins the transaction again.
+//
+// The hook is responsible for bounding the number of retries; no retry happens once ctx is done.
+func OnBeginFailure(hook func(ctx context.Context, err error) bool) Option {
+	return func(c *config) {
+		c.onBeginFailure = hook
+	}
+}
+
+// WithPanicOnCommitError makes InTx panic with the wrapped commit error instead of returning it.
+//
+// Dangerous: a failed commit leaves the outcome of the transaction uncertain, and this option
+// is meant for codebases that prefer to crash rather than risk the error being ignored.
+// The rollback still runs and the rollback event is still reported before the panic propagates.
+func WithPanicOnCommitError() Option {
+	return func(c *config) {
+		c.panicOnCommit = true
+	}
+}
+
+func (slf *config) setupTx(ctx context.Context, tx *sql.Tx) error {
+	for _, fn := range slf.setup {
+		err := fn(ctx, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (slf *config) wrap(st *txState, tx Transaction) Transaction {
+	if slf.stmtCache {
+		tx = &stmtCacheTx{Transaction: tx, stmts: map[string]*sql.Stmt{}}
+	}
+
+	for _, w := range slf.wrappers {
+		tx = w(st, tx)
+	}
+
+	if st.guarded {
+		tx = &guardTx{Transaction: tx}
+	}
+
+	return tx
+}
@@ -0,0 +1,64 @@
+package trm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrOutboxDisabled = errors.New("trm: outbox is not configured")
+
+type outbox struct {
+	insert string
+	notify func(ctx context.Context)
+}
+
+// WithOutbox enables EnqueueOutbox. Messages are inserted into table, which must have
+// topic and payload columns, e.g.
+//
+//	CREATE TABLE outbox (id BIGSERIAL PRIMARY KEY, topic TEXT NOT NULL, payload BYTEA NOT NULL)
+//
+// table may be qualified with its schema, e.g. events.outbox.
+//
+// notify is called once after a transaction that enqueued messages commits, so a relay can flush
+// the table; it is never called for rolled back transactions.
+func WithOutbox(table string, notify func(ctx context.Context)) Option {
+	return func(c *config) {
+		c.outbox = &outbox{
+			insert: "INSERT INTO " + quoteTable(table) + " (topic, payload) VALUES ($1, $2)",
+			notify: notify,
+		}
+	}
+}
+
+// EnqueueOutbox writes a message to the outbox table within the transaction carried by ctx,
+// so it becomes visible atomically with the business changes of the transaction. The insert goes
+// through the wrappers of the transaction like any statement of the callback.
+func EnqueueOutbox(ctx context.Context, topic string, payload []byte) error {
+	st := stateFrom(ctx)
+	if st == nil {
+		return fmt.Errorf("enqueue outbox: %w", ErrNoTransaction)
+	}
+
+	if st.cfg.outbox == nil {
+		return fmt.Errorf("enqueue outbox: %w", ErrOutboxDisabled)
+	}
+
+	_, err := st.txn.ExecContext(ctx, st.cfg.outbox.insert, topic, payload)
+	if err != nil {
+		return fmt.Errorf("enqueue outbox: %w", err)
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if !st.outbox {
+		st.outbox = true
+		st.onCommit = append(st.onCommit, func(ctx context.Context) error {
+			st.cfg.outbox.notify(ctx)
+			return nil
+		})
+	}
+
+	return nil
+}
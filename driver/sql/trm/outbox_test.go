@@ -0,0 +1,170 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type Outbox struct {
+	suite.Suite
+
+	ctx      context.Context
+	db       *sql.DB
+	mock     sqlmock.Sqlmock
+	impl     *trm.Impl[*txRepo]
+	notified int
+}
+
+func (slf *Outbox) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.db = db
+	slf.mock = mock
+	slf.notified = 0
+	slf.impl = trm.New(db, &txRepo{},
+		trm.WithOutbox("outbox", func(context.Context) { slf.notified++ }),
+		trm.WithIDGenerator(func() string { return "tx" }),
+	)
+}
+
+func (slf *Outbox) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *Outbox) TestEnqueueAndNotifyOnce() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("INSERT INTO orders (item) VALUES ($1)").WithArgs("a").WillReturnResult(sqlmock.NewResult(1, 1))
+	slf.mock.ExpectExec(`INSERT INTO "outbox" (topic, payload) VALUES ($1, $2)`).
+		WithArgs("orders", []byte("1")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	slf.mock.ExpectExec(`INSERT INTO "outbox" (topic, payload) VALUES ($1, $2)`).
+		WithArgs("orders", []byte("2")).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, r *txRepo) error {
+		_, err := r.tx.ExecContext(ctx, "INSERT INTO orders (item) VALUES ($1)", "a")
+		if err != nil {
+			return err
+		}
+
+		err = trm.EnqueueOutbox(ctx, "orders", []byte("1"))
+		if err != nil {
+			return err
+		}
+
+		err = trm.EnqueueOutbox(ctx, "orders", []byte("2"))
+		slf.Zero(slf.notified)
+
+		return err
+	})
+	slf.Require().NoError(err)
+	slf.Equal(1, slf.notified)
+}
+
+func (slf *Outbox) TestRollbackDoesNotNotify() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec(`INSERT INTO "outbox" (topic, payload) VALUES ($1, $2)`).
+		WithArgs("orders", []byte("1")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		err := trm.EnqueueOutbox(ctx, "orders", []byte("1"))
+		if err != nil {
+			return err
+		}
+
+		return errors.New("err")
+	})
+	slf.Require().Error(err)
+	slf.Zero(slf.notified)
+}
+
+func (slf *Outbox) TestSavepointRollbackDoesNotNotify() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SAVEPOINT sp_1_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectExec(`INSERT INTO "outbox" (topic, payload) VALUES ($1, $2)`).
+		WithArgs("orders", []byte("1")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	slf.mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		err := trm.NewSavepoint[*txRepo](ctx).Run(func(*txRepo) error {
+			err := trm.EnqueueOutbox(ctx, "orders", []byte("1"))
+			if err != nil {
+				return err
+			}
+
+			return errors.New("err")
+		})
+		slf.Require().Error(err)
+
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Zero(slf.notified)
+}
+
+func (slf *Outbox) TestCommitFailureDoesNotNotify() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec(`INSERT INTO "outbox" (topic, payload) VALUES ($1, $2)`).
+		WithArgs("orders", []byte("1")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	slf.mock.ExpectCommit().WillReturnError(errors.New("err"))
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		return trm.EnqueueOutbox(ctx, "orders", []byte("1"))
+	})
+	slf.Require().EqualError(err, "commit tx: err")
+	slf.Zero(slf.notified)
+}
+
+func (slf *Outbox) TestSchemaQualifiedTable() {
+	slf.impl = trm.New(slf.db, &txRepo{}, trm.WithOutbox("events.outbox", func(context.Context) { slf.notified++ }))
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec(`INSERT INTO "events"."outbox" (topic, payload) VALUES ($1, $2)`).
+		WithArgs("orders", []byte("1")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		return trm.EnqueueOutbox(ctx, "orders", []byte("1"))
+	})
+	slf.Require().NoError(err)
+	slf.Equal(1, slf.notified)
+}
+
+func (slf *Outbox) TestDisabled() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+	slf.mock = mock
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err = trm.New(db, &txRepo{}).InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		return trm.EnqueueOutbox(ctx, "orders", nil)
+	})
+	slf.Require().ErrorIs(err, trm.ErrOutboxDisabled)
+}
+
+func (slf *Outbox) TestOutsideTransaction() {
+	err := trm.EnqueueOutbox(slf.ctx, "orders", nil)
+	slf.Require().ErrorIs(err, trm.ErrNoTransaction)
+}
+
+func TestOutbox(t *testing.T) {
+	suite.Run(t, new(Outbox))
+}
@@ -0,0 +1,54 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type PanicOnCommitError struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+}
+
+func (slf *PanicOnCommitError) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithPanicOnCommitError())
+}
+
+func (slf *PanicOnCommitError) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *PanicOnCommitError) TestPanics() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit().WillReturnError(errors.New("err"))
+
+	slf.PanicsWithError("commit tx: err", func() {
+		_ = slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	})
+}
+
+func (slf *PanicOnCommitError) TestCallbackErrorReturned() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return errors.New("err") })
+	slf.Require().EqualError(err, "trm callback: err")
+}
+
+func TestPanicOnCommitError(t *testing.T) {
+	suite.Run(t, new(PanicOnCommitError))
+}
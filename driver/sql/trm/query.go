@@ -0,0 +1,24 @@
+package trm
+
+import (
+	"context"
+	"fmt"
+)
+
+// Query runs fn with the database handle itself, for reads that do not need a transaction:
+// every statement runs in autocommit mode, with no begin or commit round trips.
+// The database is picked the same way as for InTx, so a shard resolver applies too.
+// Transaction options, retries, wrappers and hooks do not apply.
+func (slf *impl[T]) Query(ctx context.Context, fn func(q Query) error) error {
+	db, err := slf.resolveDB(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = fn(db)
+	if err != nil {
+		return fmt.Errorf("trm callback: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type Query struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+}
+
+func (slf *Query) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{})
+}
+
+func (slf *Query) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *Query) TestRunsWithoutTransaction() {
+	slf.mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+
+	var n int
+	err := slf.impl.Query(slf.ctx, func(q trm.Query) error {
+		return q.QueryRowContext(slf.ctx, "SELECT 1").Scan(&n)
+	})
+	slf.Require().NoError(err)
+	slf.Equal(1, n)
+}
+
+func (slf *Query) TestCallbackError() {
+	expected := errors.New("read")
+
+	err := slf.impl.Query(slf.ctx, func(trm.Query) error {
+		return expected
+	})
+	slf.Require().ErrorIs(err, expected)
+}
+
+func TestQuery(t *testing.T) {
+	suite.Run(t, new(Query))
+}
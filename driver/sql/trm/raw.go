@@ -0,0 +1,31 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+)
+
+// RawTx returns the *sql.Tx of the transaction carried by ctx, the context InTxCtx passes to its
+// callback, and false outside a transaction. With WithLazyBegin it begins the transaction first
+// and returns false if that fails.
+//
+// It is an escape hatch for driver-specific features the Transaction interface does not cover,
+// e.g. pq.CopyIn, and couples the caller to database/sql: statements executed on the raw
+// transaction bypass every wrapper (query tags, the statement cache, counters), so
+// WithQueryRowCache stops caching in the transaction. It returns false in a transaction of
+// ReadOnlyGuard, whose check it would bypass.
+func RawTx(ctx context.Context) (*sql.Tx, bool) {
+	st := stateFrom(ctx)
+	if st == nil || st.guarded {
+		return nil, false
+	}
+
+	tx, err := st.rawTx()
+	if err != nil || tx == nil {
+		return nil, false
+	}
+
+	st.rowCache.bypass()
+
+	return tx, true
+}
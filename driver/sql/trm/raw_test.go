@@ -0,0 +1,96 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type RawTx struct {
+	suite.Suite
+
+	mock sqlmock.Sqlmock
+}
+
+func (slf *RawTx) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *RawTx) newImpl(opts ...trm.Option) *trm.Impl[*mockWithTx] {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+
+	return trm.New(db, &mockWithTx{}, opts...)
+}
+
+func (slf *RawTx) TestInTransaction() {
+	impl := slf.newImpl()
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("COPY orders FROM STDIN").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit()
+
+	err := impl.InTxCtx(context.Background(), func(ctx context.Context, _ *mockWithTx) error {
+		tx, ok := trm.RawTx(ctx)
+		slf.Require().True(ok)
+
+		_, err := tx.ExecContext(ctx, "COPY orders FROM STDIN")
+
+		return err
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *RawTx) TestOutsideTransaction() {
+	slf.newImpl()
+
+	tx, ok := trm.RawTx(context.Background())
+	slf.False(ok)
+	slf.Nil(tx)
+}
+
+func (slf *RawTx) TestLazyBeginFailure() {
+	impl := slf.newImpl(trm.WithLazyBegin())
+
+	slf.mock.ExpectBegin().WillReturnError(errors.New("begin"))
+
+	err := impl.InTxCtx(context.Background(), func(ctx context.Context, _ *mockWithTx) error {
+		_, ok := trm.RawTx(ctx)
+		slf.False(ok)
+
+		return nil
+	})
+	slf.Require().ErrorContains(err, "begin")
+}
+
+func (slf *RawTx) TestQueryAfterAbort() {
+	impl := slf.newImpl()
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := impl.InTxCtx(context.Background(), func(ctx context.Context, _ *mockWithTx) error {
+		tx, ok := trm.RawTx(ctx)
+		slf.Require().True(ok)
+		slf.Require().NoError(tx.Rollback())
+
+		_, err := tx.ExecContext(ctx, "UPDATE orders SET status = 'paid'")
+
+		return err
+	})
+	slf.Require().ErrorIs(err, trm.ErrTxAborted)
+	slf.Require().ErrorIs(err, sql.ErrTxDone)
+	slf.Require().EqualError(err, "trm: transaction aborted: trm callback: "+sql.ErrTxDone.Error())
+}
+
+func TestRawTx(t *testing.T) {
+	suite.Run(t, new(RawTx))
+}
@@ -0,0 +1,55 @@
+package trm
+
+import "context"
+
+// RollbackReason tells why a transaction was rolled back, see Event.Reason and TxMeta.
+type RollbackReason int
+
+const (
+	// RollbackNone means the transaction was not rolled back.
+	RollbackNone RollbackReason = iota
+	// RollbackError means the callback returned an error.
+	RollbackError
+	// RollbackCanceled means the transaction context was done when the transaction failed,
+	// whichever step noticed it.
+	RollbackCanceled
+	// RollbackPanic means a panic unwound the transaction.
+	RollbackPanic
+	// RollbackVetoed means a DeferInTx function or the WithBeforeCommit hook failed,
+	// or SetRollbackOnly marked the transaction.
+	RollbackVetoed
+	// RollbackCommitFailed means the commit itself failed.
+	RollbackCommitFailed
+	// RollbackSetupFailed means setting up the begun transaction failed, e.g. a setup option.
+	RollbackSetupFailed
+)
+
+func (r RollbackReason) String() string {
+	switch r {
+	case RollbackNone:
+		return "none"
+	case RollbackError:
+		return "error"
+	case RollbackCanceled:
+		return "canceled"
+	case RollbackPanic:
+		return "panic"
+	case RollbackVetoed:
+		return "vetoed"
+	case RollbackCommitFailed:
+		return "commit_failed"
+	case RollbackSetupFailed:
+		return "setup_failed"
+	default:
+		return "unknown"
+	}
+}
+
+// failed records why st is about to be rolled back, unless ctx is done, which takes precedence.
+func (slf *txState) failed(ctx context.Context, reason RollbackReason) {
+	if ctx.Err() != nil {
+		reason = RollbackCanceled
+	}
+
+	slf.rollbackReason = reason
+}
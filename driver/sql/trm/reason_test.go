@@ -0,0 +1,120 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type RollbackReason struct {
+	suite.Suite
+
+	ctx     context.Context
+	mock    sqlmock.Sqlmock
+	impl    *trm.Impl[*mockWithTx]
+	reasons []trm.RollbackReason
+	veto    error
+}
+
+func (slf *RollbackReason) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.reasons = nil
+	slf.veto = nil
+	slf.impl = trm.New(db, &mockWithTx{},
+		trm.WithBeforeCommit(func(context.Context) error {
+			return slf.veto
+		}),
+		trm.WithEventSink(func(_ context.Context, e trm.Event) {
+			if e.Kind == trm.EventRollback {
+				slf.reasons = append(slf.reasons, e.Reason)
+			}
+		}),
+	)
+}
+
+func (slf *RollbackReason) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *RollbackReason) TestCommitted() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	meta, err := slf.impl.InTxMeta(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+	slf.Equal(trm.RollbackNone, meta.RollbackReason)
+	slf.Empty(slf.reasons)
+}
+
+func (slf *RollbackReason) TestError() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	meta, err := slf.impl.InTxMeta(slf.ctx, func(*mockWithTx) error { return errors.New("failed") })
+	slf.Require().Error(err)
+	slf.Equal(trm.RollbackError, meta.RollbackReason)
+	slf.Equal([]trm.RollbackReason{trm.RollbackError}, slf.reasons)
+}
+
+func (slf *RollbackReason) TestCanceled() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	ctx, cancel := context.WithCancel(slf.ctx)
+	meta, err := slf.impl.InTxMeta(ctx, func(*mockWithTx) error {
+		cancel()
+		return ctx.Err()
+	})
+	slf.Require().ErrorIs(err, context.Canceled)
+	slf.Equal(trm.RollbackCanceled, meta.RollbackReason)
+	slf.Equal([]trm.RollbackReason{trm.RollbackCanceled}, slf.reasons)
+}
+
+func (slf *RollbackReason) TestPanic() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	slf.PanicsWithValue("boom", func() {
+		_ = slf.impl.InTx(slf.ctx, func(*mockWithTx) error { panic("boom") })
+	})
+	slf.Equal([]trm.RollbackReason{trm.RollbackPanic}, slf.reasons)
+}
+
+func (slf *RollbackReason) TestVetoed() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	slf.veto = errors.New("veto")
+	meta, err := slf.impl.InTxMeta(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().ErrorIs(err, slf.veto)
+	slf.Equal(trm.RollbackVetoed, meta.RollbackReason)
+	slf.Equal([]trm.RollbackReason{trm.RollbackVetoed}, slf.reasons)
+}
+
+func (slf *RollbackReason) TestCommitFailed() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit().WillReturnError(errors.New("commit failed"))
+
+	meta, err := slf.impl.InTxMeta(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().Error(err)
+	slf.Equal(trm.RollbackCommitFailed, meta.RollbackReason)
+	slf.Equal([]trm.RollbackReason{trm.RollbackCommitFailed}, slf.reasons)
+}
+
+func (slf *RollbackReason) TestString() {
+	slf.Equal("canceled", trm.RollbackCanceled.String())
+	slf.Equal("unknown", trm.RollbackReason(100).String())
+}
+
+func TestRollbackReason(t *testing.T) {
+	suite.Run(t, new(RollbackReason))
+}
@@ -0,0 +1,207 @@
+package trm
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// WithReplayBuffer records the statements executed through the transaction, so a failed
+// transaction can be dumped as the exact sequence of statements it ran, see ReplayFrom.
+//
+// Only the last size statements of an attempt are kept; earlier ones are counted in
+// Replay.Dropped. Arguments are never reported as is: each one is replaced with a short
+// keyed hash of its value, which tells equal values apart within the process without leaking
+// them into logs. The hashes are only computed when a failed transaction is reported.
+func WithReplayBuffer(size int) Option {
+	return func(c *config) {
+		c.wrappers = append(c.wrappers, func(st *txState, tx Transaction) Transaction {
+			if !st.sampled {
+				return tx
+			}
+
+			st.replay = &replayBuffer{size: max(size, 1)}
+			return &replayTx{Transaction: tx, buf: st.replay}
+		})
+	}
+}
+
+// Replay lists the statements executed by a failed transaction attempt, oldest first.
+type Replay struct {
+	Statements []ReplayStatement
+	// Dropped is the number of earlier statements that did not fit into the buffer.
+	Dropped int
+}
+
+// ReplayStatement is a statement recorded by WithReplayBuffer.
+type ReplayStatement struct {
+	Query string
+	// Args holds a keyed hash of every argument, e.g. hmac:9f86d081. The key is random
+	// and the same for the whole process, so hashes cannot be compared across processes.
+	Args []string
+	// Err is the error returned by the statement. Errors of QueryRowContext are not seen.
+	Err error
+}
+
+// String formats r one statement per line, e.g. for a log record.
+func (slf *Replay) String() string {
+	var b strings.Builder
+	if slf.Dropped > 0 {
+		fmt.Fprintf(&b, "... %d statements dropped\n", slf.Dropped)
+	}
+
+	for i, s := range slf.Statements {
+		fmt.Fprintf(&b, "%d. %s", slf.Dropped+i+1, s.Query)
+		if len(s.Args) > 0 {
+			b.WriteString(" [" + strings.Join(s.Args, ", ") + "]")
+		}
+
+		if s.Err != nil {
+			b.WriteString(": " + s.Err.Error())
+		}
+
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// ReplayFrom returns the statements recorded by WithReplayBuffer in the final attempt
+// of the transaction that failed with err.
+func ReplayFrom(err error) (*Replay, bool) {
+	var r *replayError
+	if !errors.As(err, &r) {
+		return nil, false
+	}
+
+	return r.replay, true
+}
+
+type replayError struct {
+	err    error
+	replay *Replay
+}
+
+func (e *replayError) Error() string {
+	return e.err.Error()
+}
+
+func (e *replayError) Unwrap() error {
+	return e.err
+}
+
+// withReplay attaches the statements recorded in st to err.
+func (slf *txState) withReplay(err error) error {
+	if err == nil || slf == nil || slf.replay == nil {
+		return err
+	}
+
+	return &replayError{err: err, replay: slf.replay.snapshot()}
+}
+
+// replayKey is the key of the argument hashes, drawn once per process.
+var replayKey = sync.OnceValue(func() []byte { //nolint:gochecknoglobals // one key per process
+	key := make([]byte, sha256.Size)
+	_, _ = rand.Read(key) // never fails, see crypto/rand.Read
+
+	return key
+})
+
+type replayBuffer struct {
+	mu      sync.Mutex
+	size    int
+	stmts   []recordedStatement
+	dropped int
+}
+
+// recordedStatement is a statement as executed; its arguments are hashed by snapshot.
+type recordedStatement struct {
+	query string
+	args  []any
+	err   error
+}
+
+func (slf *replayBuffer) record(query string, args []any, err error) {
+	kept := make([]any, len(args))
+	for i, arg := range args {
+		if b, ok := arg.([]byte); ok {
+			// The caller may reuse its buffer once the statement returned.
+			arg = bytes.Clone(b)
+		}
+
+		kept[i] = arg
+	}
+
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	if len(slf.stmts) == slf.size {
+		slf.stmts = slf.stmts[1:]
+		slf.dropped++
+	}
+
+	slf.stmts = append(slf.stmts, recordedStatement{query: query, args: kept, err: err})
+}
+
+func (slf *replayBuffer) snapshot() *Replay {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	replay := &Replay{Statements: make([]ReplayStatement, len(slf.stmts)), Dropped: slf.dropped}
+	for i, s := range slf.stmts {
+		replay.Statements[i] = ReplayStatement{Query: s.query, Args: hashArgs(s.args), Err: s.err}
+	}
+
+	return replay
+}
+
+func hashArgs(args []any) []string {
+	hashed := make([]string, len(args))
+	for i, arg := range args {
+		mac := hmac.New(sha256.New, replayKey())
+		fmt.Fprintf(mac, "%T:%v", arg, arg)
+		hashed[i] = "hmac:" + hex.EncodeToString(mac.Sum(nil)[:4])
+	}
+
+	return hashed
+}
+
+type replayTx struct {
+	Transaction
+
+	buf *replayBuffer
+}
+
+func (slf *replayTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	res, err := slf.Transaction.ExecContext(ctx, query, args...)
+	slf.buf.record(query, args, err)
+
+	return res, err
+}
+
+func (slf *replayTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	stmt, err := slf.Transaction.PrepareContext(ctx, query)
+	slf.buf.record("PREPARE "+query, nil, err)
+
+	return stmt, err
+}
+
+func (slf *replayTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	rows, err := slf.Transaction.QueryContext(ctx, query, args...)
+	slf.buf.record(query, args, err)
+
+	return rows, err
+}
+
+func (slf *replayTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	slf.buf.record(query, args, nil)
+	return slf.Transaction.QueryRowContext(ctx, query, args...)
+}
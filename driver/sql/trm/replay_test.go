@@ -0,0 +1,104 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type ReplayBuffer struct {
+	suite.Suite
+
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *ReplayBuffer) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+	slf.impl = trm.New(db, &txRepo{}, trm.WithReplayBuffer(2))
+}
+
+func (slf *ReplayBuffer) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *ReplayBuffer) TestFailedTransaction() {
+	failed := errors.New("duplicate")
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("INSERT INTO a VALUES ($1)").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+	slf.mock.ExpectExec("INSERT INTO b VALUES ($1)").WithArgs("secret").WillReturnResult(sqlmock.NewResult(1, 1))
+	slf.mock.ExpectExec("INSERT INTO c VALUES ($1)").WithArgs("secret").WillReturnError(failed)
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(context.Background(), func(r *txRepo) error {
+		_, err := r.tx.ExecContext(context.Background(), "INSERT INTO a VALUES ($1)", 1)
+		slf.Require().NoError(err)
+		_, err = r.tx.ExecContext(context.Background(), "INSERT INTO b VALUES ($1)", "secret")
+		slf.Require().NoError(err)
+		_, err = r.tx.ExecContext(context.Background(), "INSERT INTO c VALUES ($1)", "secret")
+
+		return err
+	})
+	slf.Require().ErrorIs(err, failed)
+
+	replay, ok := trm.ReplayFrom(err)
+	slf.Require().True(ok)
+	slf.Equal(1, replay.Dropped)
+	slf.Require().Len(replay.Statements, 2)
+	slf.Equal("INSERT INTO b VALUES ($1)", replay.Statements[0].Query)
+	slf.NoError(replay.Statements[0].Err)
+	slf.Equal(replay.Statements[0].Args, replay.Statements[1].Args)
+	slf.NotContains(replay.String(), "secret")
+	slf.ErrorIs(replay.Statements[1].Err, failed)
+	slf.Contains(replay.String(), "3. INSERT INTO c VALUES ($1) [hmac:")
+}
+
+func (slf *ReplayBuffer) TestReusedBuffer() {
+	failed := errors.New("duplicate")
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("INSERT INTO a VALUES ($1)").WillReturnResult(sqlmock.NewResult(1, 1))
+	slf.mock.ExpectExec("INSERT INTO b VALUES ($1)").WillReturnError(failed)
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(context.Background(), func(r *txRepo) error {
+		buf := []byte("secret")
+		_, err := r.tx.ExecContext(context.Background(), "INSERT INTO a VALUES ($1)", buf)
+		slf.Require().NoError(err)
+
+		copy(buf, "public")
+		_, err = r.tx.ExecContext(context.Background(), "INSERT INTO b VALUES ($1)", []byte("secret"))
+
+		return err
+	})
+	slf.Require().ErrorIs(err, failed)
+
+	replay, ok := trm.ReplayFrom(err)
+	slf.Require().True(ok)
+	slf.Require().Len(replay.Statements, 2)
+	slf.Equal(replay.Statements[0].Args, replay.Statements[1].Args)
+}
+
+func (slf *ReplayBuffer) TestCommittedTransaction() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(context.Background(), func(*txRepo) error { return nil })
+	slf.Require().NoError(err)
+
+	_, ok := trm.ReplayFrom(err)
+	slf.False(ok)
+}
+
+func TestReplayBuffer(t *testing.T) {
+	suite.Run(t, new(ReplayBuffer))
+}
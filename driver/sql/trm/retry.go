@@ -0,0 +1,30 @@
+package trm
+
+import (
+	"context"
+)
+
+type retryEnabledKey struct{}
+
+// WithRetryDefault sets whether rolled back attempts are passed to WithRollbackDecider,
+// true unless set. Disable it to roll out a decider gradually with WithRetryEnabled.
+func WithRetryDefault(enabled bool) Option {
+	return func(c *config) {
+		c.retryDisabled = !enabled
+	}
+}
+
+// WithRetryEnabled returns a context whose transactions are retried by WithRollbackDecider
+// only if enabled, overriding WithRetryDefault, e.g. to enable a new retry policy for a share
+// of the traffic and compare error rates. Begin failures are still passed to OnBeginFailure.
+func WithRetryEnabled(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, retryEnabledKey{}, enabled)
+}
+
+func (slf *config) retryEnabled(ctx context.Context) bool {
+	if enabled, ok := ctx.Value(retryEnabledKey{}).(bool); ok {
+		return enabled
+	}
+
+	return !slf.retryDisabled
+}
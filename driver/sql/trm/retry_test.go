@@ -0,0 +1,217 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type RollbackDecider struct {
+	suite.Suite
+
+	ctx      context.Context
+	mock     sqlmock.Sqlmock
+	impl     *trm.Impl[*mockWithTx]
+	attempts []int
+}
+
+func (slf *RollbackDecider) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.attempts = nil
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithRollbackDecider(func(_ context.Context, attempt int, _ error) bool {
+		slf.attempts = append(slf.attempts, attempt)
+		return attempt < 3
+	}))
+}
+
+func (slf *RollbackDecider) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *RollbackDecider) TestRetryUntilSuccess() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	calls := 0
+	err := slf.impl.InTx(slf.ctx, func(_ *mockWithTx) error {
+		calls++
+		if calls == 1 {
+			return errors.New("err")
+		}
+
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal(2, calls)
+	slf.Equal([]int{1}, slf.attempts)
+}
+
+func (slf *RollbackDecider) TestDeciderStops() {
+	for range 3 {
+		slf.mock.ExpectBegin()
+		slf.mock.ExpectRollback()
+	}
+
+	err := slf.impl.InTx(slf.ctx, func(_ *mockWithTx) error {
+		return errors.New("err")
+	})
+	slf.Require().EqualError(err, "trm callback: err")
+	slf.Equal([]int{1, 2, 3}, slf.attempts)
+}
+
+func (slf *RollbackDecider) TestCommitFailure() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit().WillReturnError(errors.New("err"))
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(slf.ctx, func(_ *mockWithTx) error {
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal([]int{1}, slf.attempts)
+}
+
+func (slf *RollbackDecider) TestBeginFailureNotDecided() {
+	slf.mock.ExpectBegin().WillReturnError(errors.New("err"))
+
+	err := slf.impl.InTx(slf.ctx, func(_ *mockWithTx) error {
+		return nil
+	})
+	slf.Require().EqualError(err, "begin tx: err")
+	slf.Empty(slf.attempts)
+}
+
+func (slf *RollbackDecider) TestCancelledContext() {
+	ctx, cancel := context.WithCancel(slf.ctx)
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(ctx, func(_ *mockWithTx) error {
+		cancel()
+		return errors.New("err")
+	})
+	slf.Require().EqualError(err, "trm callback: err")
+	slf.Empty(slf.attempts)
+}
+
+func (slf *RollbackDecider) TestInTxAttempts() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	calls := 0
+	attempts, err := slf.impl.InTxAttempts(slf.ctx, func(_ *mockWithTx) error {
+		calls++
+		if calls == 1 {
+			return errors.New("err")
+		}
+
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal(2, attempts)
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	attempts, err = slf.impl.InTxAttempts(slf.ctx, func(_ *mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+	slf.Equal(1, attempts)
+}
+
+func (slf *RollbackDecider) TestNoRetry() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	calls := 0
+	err := slf.impl.InTxWith(slf.ctx, func(_ *mockWithTx) error {
+		calls++
+		return errors.New("err")
+	}, trm.NoRetry())
+	slf.Require().EqualError(err, "trm callback: err")
+	slf.Equal(1, calls)
+	slf.Empty(slf.attempts)
+}
+
+func TestRollbackDecider(t *testing.T) {
+	suite.Run(t, new(RollbackDecider))
+}
+
+type RetryEnabled struct {
+	suite.Suite
+
+	mock     sqlmock.Sqlmock
+	attempts []int
+}
+
+func (slf *RetryEnabled) SetupTest() {
+	slf.attempts = nil
+}
+
+func (slf *RetryEnabled) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *RetryEnabled) newImpl(opts ...trm.Option) *trm.Impl[*mockWithTx] {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+	opts = append(opts, trm.WithRollbackDecider(func(_ context.Context, attempt int, _ error) bool {
+		slf.attempts = append(slf.attempts, attempt)
+		return attempt < 2
+	}))
+
+	return trm.New(db, &mockWithTx{}, opts...)
+}
+
+func (slf *RetryEnabled) TestDisabledByContext() {
+	impl := slf.newImpl()
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	ctx := trm.WithRetryEnabled(context.Background(), false)
+	err := impl.InTx(ctx, func(*mockWithTx) error { return errors.New("err") })
+	slf.Require().EqualError(err, "trm callback: err")
+	slf.Empty(slf.attempts)
+}
+
+func (slf *RetryEnabled) TestDisabledByDefault() {
+	impl := slf.newImpl(trm.WithRetryDefault(false))
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := impl.InTx(context.Background(), func(*mockWithTx) error { return errors.New("err") })
+	slf.Require().Error(err)
+	slf.Empty(slf.attempts)
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	ctx := trm.WithRetryEnabled(context.Background(), true)
+	err = impl.InTx(ctx, func(*mockWithTx) error { return errors.New("err") })
+	slf.Require().Error(err)
+	slf.Equal([]int{1, 2}, slf.attempts)
+}
+
+func TestRetryEnabled(t *testing.T) {
+	suite.Run(t, new(RetryEnabled))
+}
@@ -0,0 +1,32 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrNoReturning = errors.New("trm: statement has no RETURNING clause")
+
+// ExecReturning executes an INSERT, UPDATE or DELETE ... RETURNING statement inside tx and returns
+// the rows it changed, e.g. to record them in an audit log atomically with the change. ExecContext
+// cannot be used for that, as it discards the returned rows.
+//
+// The rows belong to the transaction: read and close them before the callback returns and before
+// the next statement on tx, which shares its connection. Statements without a RETURNING keyword
+// fail with ErrNoReturning before reaching the database. As the statement runs through QueryContext,
+// it is not seen by WithModifyTracking and WithRowsAffected.
+func ExecReturning(ctx context.Context, tx Transaction, query string, args ...any) (*sql.Rows, error) {
+	if !strings.Contains(strings.ToUpper(query), "RETURNING") {
+		return nil, fmt.Errorf("exec returning: %w", ErrNoReturning)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec returning: %w", err)
+	}
+
+	return rows, nil
+}
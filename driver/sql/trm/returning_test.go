@@ -0,0 +1,81 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type ExecReturning struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *ExecReturning) SetupTest() {
+	var (
+		db  *sql.DB
+		err error
+	)
+	db, slf.mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.impl = trm.New(db, &txRepo{})
+}
+
+func (slf *ExecReturning) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *ExecReturning) TestReturnsChangedRows() {
+	query := "DELETE FROM orders WHERE item = $1 RETURNING id"
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery(query).WithArgs("a").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	slf.mock.ExpectCommit()
+
+	var ids []int
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		rows, err := trm.ExecReturning(slf.ctx, r.tx, query, "a")
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			var id int
+			if err = rows.Scan(&id); err != nil {
+				return err
+			}
+
+			ids = append(ids, id)
+		}
+
+		return rows.Err()
+	})
+	slf.Require().NoError(err)
+	slf.Equal([]int{1, 2}, ids)
+}
+
+func (slf *ExecReturning) TestWithoutReturning() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		_, err := trm.ExecReturning(slf.ctx, r.tx, "DELETE FROM orders WHERE item = $1", "a")
+		return err
+	})
+	slf.Require().ErrorIs(err, trm.ErrNoReturning)
+}
+
+func TestExecReturning(t *testing.T) {
+	suite.Run(t, new(ExecReturning))
+}
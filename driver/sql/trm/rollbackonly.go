@@ -0,0 +1,24 @@
+package trm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrRollbackOnly = errors.New("trm: transaction marked rollback-only")
+
+// SetRollbackOnly marks the transaction carried by ctx so it never commits, like JTA's
+// setRollbackOnly: once the callback returns, even nil, InTx rolls back and returns ErrRollbackOnly.
+// It lets code deep in the callback that detects an invariant violation, but has no error its
+// callers would propagate, stop the commit. The mark cannot be cleared; it is safe for concurrent use.
+func SetRollbackOnly(ctx context.Context) error {
+	st := stateFrom(ctx)
+	if st == nil || st.committed {
+		return fmt.Errorf("set rollback only: %w", ErrNoTransaction)
+	}
+
+	st.rollbackOnly.Store(true)
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type SetRollbackOnly struct {
+	suite.Suite
+
+	ctx  context.Context
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+}
+
+func (slf *SetRollbackOnly) SetupTest() {
+	var err error
+
+	slf.db, slf.mock, err = sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+}
+
+func (slf *SetRollbackOnly) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *SetRollbackOnly) TestRollsBackAlthoughCallbackSucceeded() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	var reasons []trm.RollbackReason
+	impl := trm.New(slf.db, &mockWithTx{}, trm.WithEventSink(func(_ context.Context, e trm.Event) {
+		if e.Kind == trm.EventRollback {
+			reasons = append(reasons, e.Reason)
+		}
+	}))
+
+	committed := false
+	err := impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		slf.Require().NoError(trm.OnCommit(ctx, func(context.Context) error {
+			committed = true
+			return nil
+		}))
+
+		return trm.SetRollbackOnly(ctx)
+	})
+	slf.Require().ErrorIs(err, trm.ErrRollbackOnly)
+	slf.False(committed)
+	slf.Equal([]trm.RollbackReason{trm.RollbackVetoed}, reasons)
+}
+
+func (slf *SetRollbackOnly) TestOutsideTransaction() {
+	err := trm.SetRollbackOnly(slf.ctx)
+	slf.Require().ErrorIs(err, trm.ErrNoTransaction)
+}
+
+func TestSetRollbackOnly(t *testing.T) {
+	suite.Run(t, new(SetRollbackOnly))
+}
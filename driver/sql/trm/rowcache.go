@@ -0,0 +1,208 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// WithQueryRowCache memoizes QueryRowCached reads for the lifetime of each transaction whose
+// isolation level is repeatable read or stronger, where reading a row again returns the same
+// values, saving the round trip. Under weaker or default isolation nothing is cached.
+//
+// The cache of a transaction is emptied by every other statement executed through it, EnqueueOutbox
+// included, and by a savepoint rolled back, as the transaction may have changed what it
+// read; once RawTx hands the transaction out, whose statements it cannot see, nothing more is
+// cached. It is never shared between transactions.
+func WithQueryRowCache() Option {
+	return func(c *config) {
+		c.wrappers = append(c.wrappers, func(st *txState, tx Transaction) Transaction {
+			if st.opts == nil || st.opts.Isolation < sql.LevelRepeatableRead {
+				return tx
+			}
+
+			st.rowCache = &rowCache{rows: map[string]cachedRow{}}
+
+			return &rowCacheTx{Transaction: tx, cache: st.rowCache}
+		})
+	}
+}
+
+// QueryRowCached is q.QueryRowContext(ctx, query, args...), memoized with WithQueryRowCache when q
+// is the transaction carried by ctx. Use it for reads only.
+//
+// *sql.Row cannot be replayed, so the values scanned the first time are kept, keyed by query,
+// arguments and destination types, and copied into the destinations of later Scan calls;
+// sql.ErrNoRows is memoized too. The copies are shallow except for []byte.
+func QueryRowCached(ctx context.Context, q Query, query string, args ...any) *CachedRow {
+	return &CachedRow{ctx: ctx, q: q, query: query, args: args}
+}
+
+// CachedRow is the result of QueryRowCached.
+type CachedRow struct {
+	ctx   context.Context
+	q     Query
+	query string
+	args  []any
+}
+
+type cachedReadKey struct{}
+
+// Scan is sql.Row.Scan, served from the cache of the transaction when possible.
+func (slf *CachedRow) Scan(dest ...any) error {
+	st := stateFrom(slf.ctx)
+	if st == nil || st.rowCache == nil || slf.q != Query(st.txn) {
+		return slf.q.QueryRowContext(slf.ctx, slf.query, slf.args...).Scan(dest...)
+	}
+
+	key := rowKey(slf.query, slf.args, dest)
+	if row, ok := st.rowCache.get(key); ok {
+		row.restore(dest)
+		return row.err
+	}
+
+	ctx := context.WithValue(slf.ctx, cachedReadKey{}, true)
+
+	err := slf.q.QueryRowContext(ctx, slf.query, slf.args...).Scan(dest...)
+	if err != nil && err != sql.ErrNoRows { //nolint:errorlint // Row.Scan returns sql.ErrNoRows as is
+		return err
+	}
+
+	st.rowCache.put(key, newCachedRow(dest, err))
+
+	return err
+}
+
+func rowKey(query string, args, dest []any) string {
+	var b strings.Builder
+
+	b.WriteString(query)
+	for _, arg := range args {
+		fmt.Fprintf(&b, "\x00%T:%v", arg, arg)
+	}
+
+	for _, d := range dest {
+		fmt.Fprintf(&b, "\x00%T", d)
+	}
+
+	return b.String()
+}
+
+type cachedRow struct {
+	values []reflect.Value
+	err    error
+}
+
+func newCachedRow(dest []any, err error) cachedRow {
+	row := cachedRow{err: err}
+	if err != nil {
+		return row
+	}
+
+	row.values = make([]reflect.Value, len(dest))
+	for i, d := range dest {
+		v := reflect.ValueOf(d).Elem()
+		row.values[i] = reflect.New(v.Type()).Elem()
+		row.values[i].Set(clone(v))
+	}
+
+	return row
+}
+
+func (slf cachedRow) restore(dest []any) {
+	for i, v := range slf.values {
+		reflect.ValueOf(dest[i]).Elem().Set(clone(v))
+	}
+}
+
+// clone copies v, including the bytes of a []byte.
+func clone(v reflect.Value) reflect.Value {
+	if b, ok := v.Interface().([]byte); ok && b != nil {
+		return reflect.ValueOf(append([]byte(nil), b...)).Convert(v.Type())
+	}
+
+	return v
+}
+
+type rowCache struct {
+	mu       sync.Mutex
+	rows     map[string]cachedRow
+	bypassed bool
+}
+
+func (slf *rowCache) get(key string) (cachedRow, bool) {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	row, ok := slf.rows[key]
+
+	return row, ok
+}
+
+func (slf *rowCache) put(key string, row cachedRow) {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	if !slf.bypassed {
+		slf.rows[key] = row
+	}
+}
+
+// bypass empties the cache, which may be nil, for good: the transaction is written to where
+// the cache cannot see it.
+func (slf *rowCache) bypass() {
+	if slf == nil {
+		return
+	}
+
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	slf.bypassed = true
+	clear(slf.rows)
+}
+
+// clear empties the cache, which may be nil.
+func (slf *rowCache) clear() {
+	if slf == nil {
+		return
+	}
+
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	clear(slf.rows)
+}
+
+// rowCacheTx empties the cache on every statement but the reads of QueryRowCached.
+type rowCacheTx struct {
+	Transaction
+
+	cache *rowCache
+}
+
+func (slf *rowCacheTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	slf.cache.clear()
+	return slf.Transaction.ExecContext(ctx, query, args...)
+}
+
+func (slf *rowCacheTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	slf.cache.clear()
+	return slf.Transaction.PrepareContext(ctx, query)
+}
+
+func (slf *rowCacheTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	slf.cache.clear()
+	return slf.Transaction.QueryContext(ctx, query, args...)
+}
+
+func (slf *rowCacheTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	if cached, _ := ctx.Value(cachedReadKey{}).(bool); !cached {
+		slf.cache.clear()
+	}
+
+	return slf.Transaction.QueryRowContext(ctx, query, args...)
+}
@@ -0,0 +1,198 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+const selectUserName = "SELECT name FROM users WHERE id = $1"
+
+type QueryRowCache struct {
+	suite.Suite
+
+	ctx  context.Context
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *QueryRowCache) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.db = db
+	slf.mock = mock
+	slf.impl = trm.New(db, &txRepo{},
+		trm.WithQueryRowCache(),
+		trm.WithTxOptions(&sql.TxOptions{Isolation: sql.LevelRepeatableRead}),
+	)
+}
+
+func (slf *QueryRowCache) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *QueryRowCache) expectName(id int, name string) {
+	slf.mock.ExpectQuery(selectUserName).WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow(name))
+}
+
+func (slf *QueryRowCache) readName(ctx context.Context, q trm.Query, id int) string {
+	var name string
+
+	err := trm.QueryRowCached(ctx, q, selectUserName, id).Scan(&name)
+	slf.Require().NoError(err)
+
+	return name
+}
+
+func (slf *QueryRowCache) TestMemoized() {
+	slf.mock.ExpectBegin()
+	slf.expectName(1, "John")
+	slf.expectName(2, "Jane")
+	// Destinations of another type are keyed apart.
+	slf.expectName(2, "Jane")
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, r *txRepo) error {
+		slf.Equal("John", slf.readName(ctx, r.tx, 1))
+		slf.Equal("John", slf.readName(ctx, r.tx, 1))
+		slf.Equal("Jane", slf.readName(ctx, r.tx, 2))
+
+		for range 2 {
+			var raw []byte
+			slf.Require().NoError(trm.QueryRowCached(ctx, r.tx, selectUserName, 2).Scan(&raw))
+			slf.Equal([]byte("Jane"), raw)
+
+			raw[0] = 'X'
+		}
+
+		return nil
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *QueryRowCache) TestNoRowsMemoized() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery(selectUserName).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name"}))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, r *txRepo) error {
+		var name string
+		slf.Require().ErrorIs(trm.QueryRowCached(ctx, r.tx, selectUserName, 1).Scan(&name), sql.ErrNoRows)
+		slf.Require().ErrorIs(trm.QueryRowCached(ctx, r.tx, selectUserName, 1).Scan(&name), sql.ErrNoRows)
+
+		return nil
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *QueryRowCache) TestWriteEmptiesCache() {
+	slf.mock.ExpectBegin()
+	slf.expectName(1, "John")
+	slf.mock.ExpectExec("UPDATE users SET name = $1 WHERE id = $2").
+		WithArgs("Johnny", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.expectName(1, "Johnny")
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, r *txRepo) error {
+		slf.Equal("John", slf.readName(ctx, r.tx, 1))
+
+		_, err := r.tx.ExecContext(ctx, "UPDATE users SET name = $1 WHERE id = $2", "Johnny", 1)
+		slf.Require().NoError(err)
+
+		slf.Equal("Johnny", slf.readName(ctx, r.tx, 1))
+		slf.Equal("Johnny", slf.readName(ctx, r.tx, 1))
+
+		return nil
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *QueryRowCache) TestWritesBypassingWrappers() {
+	impl := trm.New(slf.db, &txRepo{},
+		trm.WithQueryRowCache(),
+		trm.WithOutbox("outbox", func(context.Context) {}),
+		trm.WithTxOptions(&sql.TxOptions{Isolation: sql.LevelRepeatableRead}),
+	)
+
+	slf.mock.ExpectBegin()
+	slf.expectName(1, "John")
+	slf.mock.ExpectExec(`INSERT INTO "outbox" (topic, payload) VALUES ($1, $2)`).
+		WithArgs("users", []byte("1")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	slf.expectName(1, "John")
+	slf.mock.ExpectExec("UPDATE users SET name = $1 WHERE id = $2").
+		WithArgs("Johnny", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.expectName(1, "Johnny")
+	slf.expectName(1, "Johnny")
+	slf.mock.ExpectCommit()
+
+	err := impl.InTxCtx(slf.ctx, func(ctx context.Context, r *txRepo) error {
+		slf.Equal("John", slf.readName(ctx, r.tx, 1))
+		slf.Require().NoError(trm.EnqueueOutbox(ctx, "users", []byte("1")))
+		slf.Equal("John", slf.readName(ctx, r.tx, 1))
+
+		raw, ok := trm.RawTx(ctx)
+		slf.Require().True(ok)
+
+		_, err := raw.ExecContext(ctx, "UPDATE users SET name = $1 WHERE id = $2", "Johnny", 1)
+		slf.Require().NoError(err)
+
+		slf.Equal("Johnny", slf.readName(ctx, r.tx, 1))
+		slf.Equal("Johnny", slf.readName(ctx, r.tx, 1))
+
+		return nil
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *QueryRowCache) TestNotAcrossTransactions() {
+	for range 2 {
+		slf.mock.ExpectBegin()
+		slf.expectName(1, "John")
+		slf.mock.ExpectCommit()
+
+		err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, r *txRepo) error {
+			slf.Equal("John", slf.readName(ctx, r.tx, 1))
+			return nil
+		})
+		slf.Require().NoError(err)
+	}
+}
+
+func (slf *QueryRowCache) TestReadCommittedNotCached() {
+	slf.mock.ExpectBegin()
+	slf.expectName(1, "John")
+	slf.expectName(1, "Johnny")
+	slf.mock.ExpectCommit()
+
+	ctx := trm.WithContextOptions(slf.ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	err := slf.impl.InTxCtx(ctx, func(ctx context.Context, r *txRepo) error {
+		slf.Equal("John", slf.readName(ctx, r.tx, 1))
+		slf.Equal("Johnny", slf.readName(ctx, r.tx, 1))
+
+		return nil
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *QueryRowCache) TestOutsideTransaction() {
+	slf.expectName(1, "John")
+	slf.expectName(1, "John")
+
+	slf.Equal("John", slf.readName(slf.ctx, slf.db, 1))
+	slf.Equal("John", slf.readName(slf.ctx, slf.db, 1))
+}
+
+func TestQueryRowCache(t *testing.T) {
+	suite.Run(t, new(QueryRowCache))
+}
@@ -0,0 +1,48 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+)
+
+// WithRowsAffected accumulates RowsAffected of every ExecContext executed through the transaction.
+// The total is available via RowsAffected inside the callback and via TxMeta after InTxMeta returns.
+// Results whose RowsAffected fails (unsupported by the driver or statement) are skipped.
+// Statements executed through PrepareContext are not counted.
+func WithRowsAffected() Option {
+	return func(c *config) {
+		c.wrappers = append(c.wrappers, func(st *txState, tx Transaction) Transaction {
+			return &rowsTx{Transaction: tx, st: st}
+		})
+	}
+}
+
+// RowsAffected returns the total collected by WithRowsAffected so far in the transaction carried by ctx.
+func RowsAffected(ctx context.Context) int64 {
+	st := stateFrom(ctx)
+	if st == nil {
+		return 0
+	}
+
+	return st.rowsAffected.Load()
+}
+
+type rowsTx struct {
+	Transaction
+
+	st *txState
+}
+
+func (slf *rowsTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	res, err := slf.Transaction.ExecContext(ctx, query, args...)
+	if err != nil {
+		return res, err
+	}
+
+	n, errRows := res.RowsAffected()
+	if errRows == nil {
+		slf.st.rowsAffected.Add(n)
+	}
+
+	return res, nil
+}
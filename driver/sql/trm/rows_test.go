@@ -0,0 +1,83 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type RowsAffected struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *RowsAffected) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &txRepo{}, trm.WithRowsAffected())
+}
+
+func (slf *RowsAffected) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *RowsAffected) TestTotal() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 3))
+	slf.mock.ExpectExec("UPDATE orders").WillReturnResult(sqlmock.NewErrorResult(errors.New("unknown")))
+	slf.mock.ExpectExec("DELETE FROM orders").WillReturnResult(sqlmock.NewResult(0, 2))
+	slf.mock.ExpectCommit()
+
+	meta, err := slf.impl.InTxMeta(slf.ctx, func(r *txRepo) error {
+		for _, q := range []string{"UPDATE users", "UPDATE orders", "DELETE FROM orders"} {
+			_, err := r.tx.ExecContext(slf.ctx, q)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal(int64(5), meta.RowsAffected)
+}
+
+func (slf *RowsAffected) TestContextAccessor() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 4))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, r *txRepo) error {
+		slf.Zero(trm.RowsAffected(ctx))
+
+		_, err := r.tx.ExecContext(ctx, "UPDATE users")
+		slf.Equal(int64(4), trm.RowsAffected(ctx))
+
+		return err
+	})
+	slf.Require().NoError(err)
+	slf.Zero(trm.RowsAffected(slf.ctx))
+}
+
+func (slf *RowsAffected) TestBeginError() {
+	slf.mock.ExpectBegin().WillReturnError(errors.New("err"))
+
+	meta, err := slf.impl.InTxMeta(slf.ctx, func(*txRepo) error { return nil })
+	slf.Require().Error(err)
+	slf.Equal(trm.TxMeta{}, meta)
+}
+
+func TestRowsAffected(t *testing.T) {
+	suite.Run(t, new(RowsAffected))
+}
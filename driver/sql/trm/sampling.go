@@ -0,0 +1,37 @@
+package trm
+
+import (
+	"context"
+	"math/rand/v2"
+)
+
+// WithSampling gates the expensive observability features, WithAutoExplain, WithReplayBuffer,
+// WithLockWaitSampling and CountRows, so they only run for sampled transactions, keeping them
+// off the hot path: e.g. rate 0.01 for 1% of transactions, or rate 1 with a sampler selecting
+// a tenant. A transaction attempt is sampled when sampler is nil or returns true for the InTx
+// context, and then with probability rate (0 to 1). Without WithSampling every transaction is.
+//
+// The sampling rate of WithAutoExplain still applies within sampled transactions.
+func WithSampling(rate float64, sampler func(ctx context.Context) bool) Option {
+	return func(c *config) {
+		c.sampling = &sampling{rate: rate, sampler: sampler}
+	}
+}
+
+type sampling struct {
+	rate    float64
+	sampler func(ctx context.Context) bool
+}
+
+// sample decides whether the observability features run for a transaction attempt.
+func (slf *config) sample(ctx context.Context) bool {
+	if slf.sampling == nil {
+		return true
+	}
+
+	if slf.sampling.sampler != nil && !slf.sampling.sampler(ctx) {
+		return false
+	}
+
+	return rand.Float64() < slf.sampling.rate //nolint:gosec // sampling needs no cryptographic randomness
+}
@@ -0,0 +1,62 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type Sampling struct {
+	suite.Suite
+}
+
+// replayed runs a failing transaction with ctx and reports whether its statements were recorded.
+func (slf *Sampling) replayed(ctx context.Context, rate float64) bool {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	failed := errors.New("duplicate")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO a VALUES ($1)").WithArgs(1).WillReturnError(failed)
+	mock.ExpectRollback()
+
+	impl := trm.New(db, &txRepo{},
+		trm.WithReplayBuffer(2),
+		trm.WithSampling(rate, func(ctx context.Context) bool {
+			return ctx.Value(tenantKey{}) == "acme"
+		}),
+	)
+
+	err = impl.InTx(ctx, func(r *txRepo) error {
+		_, err := r.tx.ExecContext(ctx, "INSERT INTO a VALUES ($1)", 1)
+		return err
+	})
+	slf.Require().ErrorIs(err, failed)
+	slf.Require().NoError(mock.ExpectationsWereMet())
+
+	_, ok := trm.ReplayFrom(err)
+
+	return ok
+}
+
+func (slf *Sampling) TestSampled() {
+	slf.True(slf.replayed(context.WithValue(context.Background(), tenantKey{}, "acme"), 1))
+}
+
+func (slf *Sampling) TestRejectedBySampler() {
+	slf.False(slf.replayed(context.WithValue(context.Background(), tenantKey{}, "other"), 1))
+}
+
+func (slf *Sampling) TestRejectedByRate() {
+	slf.False(slf.replayed(context.WithValue(context.Background(), tenantKey{}, "acme"), 0))
+}
+
+func TestSampling(t *testing.T) {
+	suite.Run(t, new(Sampling))
+}
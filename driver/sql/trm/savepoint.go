@@ -0,0 +1,93 @@
+package trm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Savepoint isolates a part of an InTxCtx callback: a failed Run rolls back
+// to the savepoint and keeps the outer transaction alive.
+type Savepoint[T any] struct {
+	ctx context.Context
+}
+
+// NewSavepoint returns a savepoint handle for the transaction carried by ctx.
+// T must be the repository type of the transactor that started the transaction.
+func NewSavepoint[T any](ctx context.Context) *Savepoint[T] {
+	return &Savepoint[T]{ctx: ctx}
+}
+
+// Run creates a savepoint, runs fn with the repository bound to the current transaction
+// and releases the savepoint. If fn fails, changes made by fn are rolled back and the error is returned.
+// The OnCommit, OnCommitAsync and DeferInTx functions fn registered are discarded with them, and so is
+// the outbox notification of messages fn enqueued, unless one was enqueued before.
+// Run returns ErrNoTransaction when the context does not carry a transaction.
+func (slf *Savepoint[T]) Run(fn func(repo T) error) error {
+	st := stateFrom(slf.ctx)
+	if st == nil {
+		return fmt.Errorf("savepoint: %w", ErrNoTransaction)
+	}
+
+	bound := st.binder.bind(st.txn)
+	repo, ok := bound.(T)
+	if !ok {
+		return fmt.Errorf("savepoint: transactor repository is %T, not %T", bound, repo)
+	}
+
+	tx, err := st.baseTx()
+	if err != nil {
+		return fmt.Errorf("create savepoint: %w", err)
+	}
+
+	name := st.nextSavepoint()
+	mark := st.markHooks()
+
+	_, err = tx.ExecContext(slf.ctx, "SAVEPOINT "+name)
+	if err != nil {
+		return fmt.Errorf("create savepoint: %w", err)
+	}
+
+	err = fn(repo)
+	if err != nil {
+		_, errRollback := tx.ExecContext(slf.ctx, "ROLLBACK TO SAVEPOINT "+name)
+		st.rowCache.clear()
+		st.resetHooks(mark)
+		if errRollback != nil {
+			return fmt.Errorf("rollback to savepoint: %w", errors.Join(err, errRollback))
+		}
+
+		return fmt.Errorf("savepoint callback: %w", err)
+	}
+
+	_, err = tx.ExecContext(slf.ctx, "RELEASE SAVEPOINT "+name)
+	if err != nil {
+		return fmt.Errorf("release savepoint: %w", err)
+	}
+
+	return nil
+}
+
+// hookMark is what was registered in a transaction when a savepoint was created.
+type hookMark struct {
+	onCommit int
+	deferred int
+	outbox   bool
+}
+
+func (slf *txState) markHooks() hookMark {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	return hookMark{onCommit: len(slf.onCommit), deferred: len(slf.deferred), outbox: slf.outbox}
+}
+
+// resetHooks discards what was registered since mark, on rollback to its savepoint.
+func (slf *txState) resetHooks(mark hookMark) {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	slf.onCommit = slf.onCommit[:mark.onCommit]
+	slf.deferred = slf.deferred[:mark.deferred]
+	slf.outbox = mark.outbox
+}
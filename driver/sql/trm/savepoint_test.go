@@ -0,0 +1,125 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type Savepoint struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *Savepoint) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &txRepo{}, trm.WithIDGenerator(func() string { return "tx" }))
+}
+
+func (slf *Savepoint) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *Savepoint) TestRelease() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SAVEPOINT sp_1_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectExec("INSERT INTO orders (item) VALUES ($1)").
+		WithArgs("a").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	slf.mock.ExpectExec("RELEASE SAVEPOINT sp_1_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		return trm.NewSavepoint[*txRepo](ctx).Run(func(r *txRepo) error {
+			_, err := r.tx.ExecContext(ctx, "INSERT INTO orders (item) VALUES ($1)", "a")
+			return err
+		})
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *Savepoint) TestRollbackToSavepointKeepsOuterTx() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SAVEPOINT sp_1_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectExec("SAVEPOINT sp_2_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectExec("RELEASE SAVEPOINT sp_2_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		sp := trm.NewSavepoint[*txRepo](ctx)
+
+		err := sp.Run(func(*txRepo) error { return errors.New("err") })
+		slf.Require().EqualError(err, "savepoint callback: err")
+
+		return sp.Run(func(*txRepo) error { return nil })
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *Savepoint) TestRollbackDiscardsHooks() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SAVEPOINT sp_1_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1_tx").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit()
+
+	var ran []string
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		slf.Require().NoError(trm.OnCommit(ctx, func(context.Context) error {
+			ran = append(ran, "outer hook")
+			return nil
+		}))
+
+		err := trm.NewSavepoint[*txRepo](ctx).Run(func(*txRepo) error {
+			slf.Require().NoError(trm.OnCommit(ctx, func(context.Context) error {
+				ran = append(ran, "inner hook")
+				return nil
+			}))
+			slf.Require().NoError(trm.DeferInTx(ctx, func(*txRepo) error {
+				ran = append(ran, "inner deferred")
+				return nil
+			}))
+
+			return errors.New("err")
+		})
+		slf.Require().Error(err)
+
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal([]string{"outer hook"}, ran)
+}
+
+func (slf *Savepoint) TestOutsideTransaction() {
+	err := trm.NewSavepoint[*txRepo](slf.ctx).Run(func(*txRepo) error { return nil })
+	slf.Require().ErrorIs(err, trm.ErrNoTransaction)
+}
+
+func (slf *Savepoint) TestRepositoryTypeMismatch() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		return trm.NewSavepoint[*mockWithTx](ctx).Run(func(*mockWithTx) error { return nil })
+	})
+	slf.Require().EqualError(
+		err,
+		"trm callback: savepoint: transactor repository is *trm_test.txRepo, not *trm_test.mockWithTx",
+	)
+}
+
+func TestSavepoint(t *testing.T) {
+	suite.Run(t, new(Savepoint))
+}
@@ -0,0 +1,48 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+)
+
+// CountRows wraps rows returned by a statement of the transaction carried by ctx so every row
+// iterated with Next is counted in Event.RowsScanned of the commit or rollback event, a proxy for
+// the cost of reads that flags transactions pulling huge result sets:
+//
+//	rows, err := slf.q.QueryContext(ctx, query)
+//	...
+//	counted := trm.CountRows(ctx, rows)
+//	defer counted.Close()
+//	for counted.Next() {
+//
+// database/sql does not let a transaction intercept Rows.Next, so rows are counted where they
+// are iterated. Iteration and Close behave as those of rows. Outside a transaction, or one not sampled
+// by WithSampling, nothing is counted.
+func CountRows(ctx context.Context, rows *sql.Rows) *CountedRows {
+	st := stateFrom(ctx)
+	if st != nil && !st.sampled {
+		st = nil
+	}
+
+	return &CountedRows{Rows: rows, st: st}
+}
+
+// CountedRows is *sql.Rows counting the rows it iterates, see CountRows.
+type CountedRows struct {
+	*sql.Rows
+
+	st *txState
+}
+
+// Next is sql.Rows.Next, counting the row it prepares.
+func (slf *CountedRows) Next() bool {
+	if !slf.Rows.Next() {
+		return false
+	}
+
+	if slf.st != nil {
+		slf.st.rowsScanned.Add(1)
+	}
+
+	return true
+}
@@ -0,0 +1,108 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type CountRows struct {
+	suite.Suite
+
+	ctx     context.Context
+	mock    sqlmock.Sqlmock
+	impl    *trm.Impl[*txRepo]
+	scanned []int64
+}
+
+func (slf *CountRows) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.scanned = nil
+	slf.impl = trm.New(db, &txRepo{}, trm.WithEventSink(func(_ context.Context, e trm.Event) {
+		if e.Kind == trm.EventCommit || e.Kind == trm.EventRollback {
+			slf.scanned = append(slf.scanned, e.RowsScanned)
+		}
+	}))
+}
+
+func (slf *CountRows) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+// readAll iterates every row of query and returns the names read.
+func (slf *CountRows) readAll(ctx context.Context, q trm.Query, query string) []string {
+	rows, err := q.QueryContext(ctx, query)
+	slf.Require().NoError(err)
+
+	counted := trm.CountRows(ctx, rows)
+	defer func() { slf.NoError(counted.Close()) }()
+
+	var names []string
+	for counted.Next() {
+		var name string
+		slf.Require().NoError(counted.Scan(&name))
+
+		names = append(names, name)
+	}
+
+	slf.Require().NoError(counted.Err())
+
+	return names
+}
+
+func (slf *CountRows) TestCommit() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery("SELECT name FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a").AddRow("b").AddRow("c"))
+	slf.mock.ExpectQuery("SELECT name FROM orders").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("d"))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, r *txRepo) error {
+		slf.Equal([]string{"a", "b", "c"}, slf.readAll(ctx, r.tx, "SELECT name FROM users"))
+		slf.Equal([]string{"d"}, slf.readAll(ctx, r.tx, "SELECT name FROM orders"))
+
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal([]int64{4}, slf.scanned)
+}
+
+func (slf *CountRows) TestRollback() {
+	errFailed := errors.New("failed")
+
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery("SELECT name FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a").AddRow("b"))
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, r *txRepo) error {
+		slf.readAll(ctx, r.tx, "SELECT name FROM users")
+		return errFailed
+	})
+	slf.Require().ErrorIs(err, errFailed)
+	slf.Equal([]int64{2}, slf.scanned)
+}
+
+func (slf *CountRows) TestOutsideTransaction() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	mock.ExpectQuery("SELECT name FROM users").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a"))
+
+	slf.Equal([]string{"a"}, slf.readAll(slf.ctx, db, "SELECT name FROM users"))
+	slf.NoError(mock.ExpectationsWereMet())
+}
+
+func TestCountRows(t *testing.T) {
+	suite.Run(t, new(CountRows))
+}
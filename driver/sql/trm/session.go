@@ -0,0 +1,56 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Session runs transactions on one pinned connection, see WithSession.
+type Session[T any] struct {
+	impl *impl[T]
+	conn *sql.Conn
+}
+
+// WithSession acquires a dedicated connection, runs fn with a Session bound to it and releases the
+// connection afterwards. Session state that lives on the connection rather than in a transaction
+// (temporary tables, session-level prepared statements, SET SESSION) is visible to every transaction
+// and statement of the session.
+//
+// Session state outlives the session: the connection returns to the pool as is,
+// so fn should reset what it changed (e.g. DISCARD ALL in PostgreSQL).
+func (slf *impl[T]) WithSession(ctx context.Context, fn func(sess *Session[T]) error) error {
+	db, err := slf.resolveDB(ctx)
+	if err != nil {
+		return err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire session: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	err = fn(&Session[T]{impl: slf, conn: conn})
+	if err != nil {
+		return fmt.Errorf("session callback: %w", err)
+	}
+
+	return nil
+}
+
+// InTx is InTx of the transactor, with the transaction begun on the session connection.
+func (slf *Session[T]) InTx(ctx context.Context, fn func(repo T) error) error {
+	_, err := slf.impl.runOn(ctx, slf.conn, nil, func(_ context.Context, repo T) error {
+		return fn(repo)
+	})
+
+	return err
+}
+
+// Query returns the session connection for statements outside a transaction, e.g. SET SESSION.
+func (slf *Session[T]) Query() Query {
+	return slf.conn
+}
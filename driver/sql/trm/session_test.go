@@ -0,0 +1,76 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type Session struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *Session) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &txRepo{})
+}
+
+func (slf *Session) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *Session) TestTransactionsOnPinnedConnection() {
+	slf.mock.ExpectExec("CREATE TEMP TABLE batch (id int)").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("INSERT INTO batch VALUES (1)").WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectCommit()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("INSERT INTO orders SELECT id FROM batch").WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.WithSession(slf.ctx, func(sess *trm.Session[*txRepo]) error {
+		_, err := sess.Query().ExecContext(slf.ctx, "CREATE TEMP TABLE batch (id int)")
+		if err != nil {
+			return err
+		}
+
+		for _, query := range []string{"INSERT INTO batch VALUES (1)", "INSERT INTO orders SELECT id FROM batch"} {
+			err = sess.InTx(slf.ctx, func(r *txRepo) error {
+				_, err := r.tx.ExecContext(slf.ctx, query)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *Session) TestCallbackError() {
+	expected := errors.New("session")
+
+	err := slf.impl.WithSession(slf.ctx, func(*trm.Session[*txRepo]) error {
+		return expected
+	})
+	slf.Require().ErrorIs(err, expected)
+}
+
+func TestSession(t *testing.T) {
+	suite.Run(t, new(Session))
+}
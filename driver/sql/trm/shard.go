@@ -0,0 +1,36 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var errNilShard = errors.New("resolver returned nil database")
+
+// WithShardResolver picks the database each InTx call runs on, e.g. by the tenant stored in ctx.
+// The resolver runs once per call: retried attempts stay on the same shard.
+// The database passed to New is not used while a resolver is set.
+func WithShardResolver(resolve func(ctx context.Context) (*sql.DB, error)) Option {
+	return func(c *config) {
+		c.resolveShard = resolve
+	}
+}
+
+func (slf *impl[T]) resolveDB(ctx context.Context) (*sql.DB, error) {
+	if slf.cfg.resolveShard == nil {
+		return slf.db, nil
+	}
+
+	db, err := slf.cfg.resolveShard(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("resolve shard: %w", err)
+	}
+
+	if db == nil {
+		return nil, fmt.Errorf("resolve shard: %w", errNilShard)
+	}
+
+	return db, nil
+}
@@ -0,0 +1,103 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type tenantKey struct{}
+
+type ShardResolver struct {
+	suite.Suite
+
+	ctx      context.Context
+	shards   map[string]*sql.DB
+	mocks    map[string]sqlmock.Sqlmock
+	resolved int
+	impl     *trm.Impl[*mockWithTx]
+}
+
+func (slf *ShardResolver) SetupTest() {
+	slf.ctx = context.Background()
+	slf.shards = map[string]*sql.DB{}
+	slf.mocks = map[string]sqlmock.Sqlmock{}
+	slf.resolved = 0
+
+	for _, name := range []string{"a", "b"} {
+		db, mock, err := sqlmock.New()
+		slf.Require().NoError(err)
+
+		slf.shards[name] = db
+		slf.mocks[name] = mock
+	}
+
+	slf.impl = trm.New(nil, &mockWithTx{},
+		trm.WithShardResolver(func(ctx context.Context) (*sql.DB, error) {
+			slf.resolved++
+
+			tenant, _ := ctx.Value(tenantKey{}).(string)
+			if tenant == "" {
+				return nil, errors.New("no tenant")
+			}
+
+			return slf.shards[tenant], nil
+		}),
+		trm.WithRollbackDecider(func(_ context.Context, attempt int, _ error) bool {
+			return attempt < 2
+		}),
+	)
+}
+
+func (slf *ShardResolver) TearDownTest() {
+	for _, mock := range slf.mocks {
+		slf.NoError(mock.ExpectationsWereMet())
+	}
+}
+
+func (slf *ShardResolver) TestRoutesByTenant() {
+	slf.mocks["b"].ExpectBegin()
+	slf.mocks["b"].ExpectCommit()
+
+	err := slf.impl.InTx(context.WithValue(slf.ctx, tenantKey{}, "b"), func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func (slf *ShardResolver) TestResolvedOncePerCall() {
+	slf.mocks["a"].ExpectBegin()
+	slf.mocks["a"].ExpectRollback()
+	slf.mocks["a"].ExpectBegin()
+	slf.mocks["a"].ExpectCommit()
+
+	calls := 0
+	err := slf.impl.InTx(context.WithValue(slf.ctx, tenantKey{}, "a"), func(*mockWithTx) error {
+		calls++
+		if calls == 1 {
+			return errors.New("err")
+		}
+
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal(1, slf.resolved)
+}
+
+func (slf *ShardResolver) TestResolveError() {
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().EqualError(err, "resolve shard: no tenant")
+}
+
+func (slf *ShardResolver) TestNilShard() {
+	err := slf.impl.InTx(context.WithValue(slf.ctx, tenantKey{}, "c"), func(*mockWithTx) error { return nil })
+	slf.Require().EqualError(err, "resolve shard: resolver returned nil database")
+}
+
+func TestShardResolver(t *testing.T) {
+	suite.Run(t, new(ShardResolver))
+}
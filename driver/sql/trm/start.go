@@ -0,0 +1,40 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// WithStartTimeFromDB replaces the local clock reading of TxStartTime with SELECT now()
+// issued right after begin. In PostgreSQL now() is the start time of the transaction itself,
+// so values stamped with TxStartTime match DEFAULT now() columns and do not depend on
+// the clock of the application host. If the query fails, the transaction is rolled back.
+func WithStartTimeFromDB() Option {
+	return func(c *config) {
+		c.setup = append(c.setup, func(ctx context.Context, tx *sql.Tx) error {
+			st := stateFrom(ctx)
+
+			err := tx.QueryRowContext(ctx, "SELECT now()").Scan(&st.startTime)
+			if err != nil {
+				return fmt.Errorf("start time: %w", err)
+			}
+
+			return nil
+		})
+	}
+}
+
+// TxStartTime returns the logical start time of the transaction carried by ctx, so every row
+// written by one transaction can be stamped with the same value. It is read from the local clock
+// right after begin, or from the database with WithStartTimeFromDB, and is the zero time outside
+// a transaction.
+func TxStartTime(ctx context.Context) time.Time {
+	st := stateFrom(ctx)
+	if st == nil {
+		return time.Time{}
+	}
+
+	return st.startTime
+}
@@ -0,0 +1,86 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type TxStartTime struct {
+	suite.Suite
+
+	ctx    context.Context
+	mock   sqlmock.Sqlmock
+	impl   *trm.Impl[*mockWithTx]
+	fromDB *trm.Impl[*mockWithTx]
+}
+
+func (slf *TxStartTime) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{})
+	slf.fromDB = trm.New(db, &mockWithTx{}, trm.WithStartTimeFromDB())
+}
+
+func (slf *TxStartTime) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *TxStartTime) TestLocalClock() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	before := time.Now()
+
+	var got time.Time
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		got = trm.TxStartTime(ctx)
+		slf.Equal(got, trm.TxStartTime(ctx))
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.False(got.Before(before))
+	slf.False(got.After(time.Now()))
+}
+
+func (slf *TxStartTime) TestFromDB() {
+	expected := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery("SELECT now()").WillReturnRows(sqlmock.NewRows([]string{"now"}).AddRow(expected))
+	slf.mock.ExpectCommit()
+
+	var got time.Time
+	err := slf.fromDB.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		got = trm.TxStartTime(ctx)
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal(expected, got)
+}
+
+func (slf *TxStartTime) TestFromDBFailureRollsBack() {
+	expected := errors.New("now")
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery("SELECT now()").WillReturnError(expected)
+	slf.mock.ExpectRollback()
+
+	err := slf.fromDB.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().ErrorIs(err, expected)
+}
+
+func (slf *TxStartTime) TestOutsideTransaction() {
+	slf.True(trm.TxStartTime(slf.ctx).IsZero())
+}
+
+func TestTxStartTime(t *testing.T) {
+	suite.Run(t, new(TxStartTime))
+}
@@ -0,0 +1,94 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type ctxKey struct{}
+
+type binder interface {
+	bind(tx Transaction) any
+}
+
+// txState is the per-transaction data carried by the context passed to InTxCtx callbacks.
+type txState struct {
+	tx        *sql.Tx
+	external  *externalTx
+	txn       Transaction
+	binder    binder
+	cfg       *config
+	stats     *stats
+	attempt   int
+	committed bool
+	startTime time.Time
+	lazy      *lazyBegin
+	idOnce    sync.Once
+	txID      string
+	replay    *replayBuffer
+	explained *explainBuffer
+	lockWait  *lockSampler
+	opts      *sql.TxOptions
+	rowCache  *rowCache
+	dryRun    bool
+	sampled   bool
+	cleanups  *cleanupList
+	// guarded transactions reject writes, see ReadOnlyGuard.
+	guarded bool
+	// lockTables are locked right after begin, see InTxLocking.
+	lockTables []string
+	// statements are recorded for WithDeterminismCheck.
+	statements *statementLog
+
+	rollbackReason RollbackReason
+	detached       detachedContexts
+
+	// mu guards spSeq, onCommit, deferred and outbox, which goroutines sharing the transaction
+	// through DetachContext may update concurrently.
+	mu       sync.Mutex
+	spSeq    int
+	onCommit []func(ctx context.Context) error
+	deferred []func() error
+	outbox   bool
+
+	rowsAffected atomic.Int64
+	rowsScanned  atomic.Int64
+	writeSet     atomic.Int64
+	modified     atomic.Bool
+	rollbackOnly atomic.Bool
+}
+
+func withState(ctx context.Context, st *txState) context.Context {
+	return context.WithValue(ctx, ctxKey{}, st)
+}
+
+func stateFrom(ctx context.Context) *txState {
+	st, _ := ctx.Value(ctxKey{}).(*txState)
+	return st
+}
+
+// nextSavepoint names savepoints sp_<seq>_<transaction id>: the sequence comes first,
+// so names stay unique if the database truncates long identifiers.
+func (slf *txState) nextSavepoint() string {
+	slf.mu.Lock()
+	slf.spSeq++
+	seq := slf.spSeq
+	slf.mu.Unlock()
+
+	return "sp_" + strconv.Itoa(seq) + "_" + identifierPart(slf.id())
+}
+
+func (slf *txState) meta() TxMeta {
+	if slf == nil {
+		return TxMeta{}
+	}
+
+	return TxMeta{
+		RowsAffected:   slf.rowsAffected.Load(),
+		RollbackReason: slf.rollbackReason,
+	}
+}
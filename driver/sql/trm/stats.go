@@ -0,0 +1,31 @@
+package trm
+
+import "sync/atomic"
+
+// TxStats are counters of the transactions run by a transactor since it was created.
+// A failed commit counts as a rollback.
+type TxStats struct {
+	Commits       int64
+	Rollbacks     int64
+	BeginFailures int64
+	// InFlight is the number of transactions begun and not yet committed or rolled back.
+	InFlight int64
+}
+
+type stats struct {
+	commits       atomic.Int64
+	rollbacks     atomic.Int64
+	beginFailures atomic.Int64
+	inFlight      atomic.Int64
+}
+
+// Stats returns a snapshot of the transaction counters, e.g. for a debug endpoint.
+// The counters are always on and cost an atomic add per event.
+func (slf *impl[T]) Stats() TxStats {
+	return TxStats{
+		Commits:       slf.stats.commits.Load(),
+		Rollbacks:     slf.stats.rollbacks.Load(),
+		BeginFailures: slf.stats.beginFailures.Load(),
+		InFlight:      slf.stats.inFlight.Load(),
+	}
+}
@@ -0,0 +1,66 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type Stats struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+}
+
+func (slf *Stats) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{})
+}
+
+func (slf *Stats) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *Stats) TestCounts() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+	slf.mock.ExpectBegin().WillReturnError(errors.New("begin"))
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit().WillReturnError(errors.New("commit"))
+
+	slf.Require().NoError(slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return nil }))
+	slf.Require().Error(slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return errors.New("callback") }))
+	slf.Require().Error(slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return nil }))
+	slf.Require().Error(slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return nil }))
+
+	slf.Equal(trm.TxStats{Commits: 1, Rollbacks: 2, BeginFailures: 1}, slf.impl.Stats())
+}
+
+func (slf *Stats) TestInFlight() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error {
+		slf.Equal(trm.TxStats{InFlight: 1}, slf.impl.Stats())
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal(trm.TxStats{Commits: 1}, slf.impl.Stats())
+}
+
+func TestStats(t *testing.T) {
+	suite.Run(t, new(Stats))
+}
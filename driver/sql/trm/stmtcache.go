@@ -0,0 +1,91 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// WithStmtCache prepares every statement executed through the transaction once and reuses the
+// *sql.Stmt for identical SQL text until the transaction ends, so repositories calling ExecContext
+// in a loop skip the parse and plan round trip. Statements are closed at commit; on rollback
+// database/sql closes statements prepared on the transaction itself.
+//
+// The cache is the innermost wrapper whatever the order of the options: every other wrapper
+// (query tags, counters, the row cache, the argument transformer, ...) still sees each statement.
+func WithStmtCache() Option {
+	return func(c *config) {
+		c.stmtCache = true
+	}
+}
+
+type stmtCacheTx struct {
+	Transaction
+
+	mu    sync.Mutex
+	stmts map[string]*sql.Stmt
+}
+
+func (slf *stmtCacheTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	stmt, err := slf.stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt.ExecContext(ctx, args...)
+}
+
+func (slf *stmtCacheTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	stmt, err := slf.stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt.QueryContext(ctx, args...)
+}
+
+func (slf *stmtCacheTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	stmt, err := slf.stmt(ctx, query)
+	if err != nil {
+		// *sql.Row cannot carry the prepare error: run the statement unprepared to surface it.
+		return slf.Transaction.QueryRowContext(ctx, query, args...)
+	}
+
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+// Commit closes the cached statements first; a close error cannot change the outcome
+// of the transaction, so it is not reported.
+func (slf *stmtCacheTx) Commit() error {
+	slf.close()
+	return slf.Transaction.Commit()
+}
+
+func (slf *stmtCacheTx) stmt(ctx context.Context, query string) (*sql.Stmt, error) {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	stmt, ok := slf.stmts[query]
+	if ok {
+		return stmt, nil
+	}
+
+	stmt, err := slf.Transaction.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	slf.stmts[query] = stmt
+
+	return stmt, nil
+}
+
+func (slf *stmtCacheTx) close() {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	for query, stmt := range slf.stmts {
+		_ = stmt.Close()
+		delete(slf.stmts, query)
+	}
+}
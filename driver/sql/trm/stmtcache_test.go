@@ -0,0 +1,122 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type StmtCache struct {
+	suite.Suite
+
+	ctx  context.Context
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *StmtCache) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.db = db
+	slf.mock = mock
+	slf.impl = trm.New(db, &txRepo{}, trm.WithStmtCache())
+}
+
+func (slf *StmtCache) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *StmtCache) TestPreparesOnce() {
+	const insert = "INSERT INTO orders (item) VALUES ($1)"
+
+	slf.mock.ExpectBegin()
+	stmt := slf.mock.ExpectPrepare(insert)
+	stmt.ExpectExec().WithArgs("a").WillReturnResult(sqlmock.NewResult(1, 1))
+	stmt.ExpectExec().WithArgs("b").WillReturnResult(sqlmock.NewResult(2, 1))
+	stmt.WillBeClosed()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		for _, item := range []string{"a", "b"} {
+			_, err := r.tx.ExecContext(slf.ctx, insert, item)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *StmtCache) TestInnermostWhateverTheOrder() {
+	const insert = "INSERT INTO orders (item) VALUES ($1)"
+
+	impl := trm.New(slf.db, &txRepo{},
+		trm.WithStmtCache(),
+		trm.WithArgTransformer(func(_ context.Context, _ string, args []any) ([]any, error) {
+			return []any{strings.ToUpper(args[0].(string))}, nil
+		}),
+		trm.WithRowsAffected(),
+	)
+
+	slf.mock.ExpectBegin()
+	stmt := slf.mock.ExpectPrepare(insert)
+	stmt.ExpectExec().WithArgs("A").WillReturnResult(sqlmock.NewResult(1, 1))
+	stmt.ExpectExec().WithArgs("B").WillReturnResult(sqlmock.NewResult(2, 1))
+	stmt.WillBeClosed()
+	slf.mock.ExpectCommit()
+
+	meta, err := impl.InTxMeta(slf.ctx, func(r *txRepo) error {
+		for _, item := range []string{"a", "b"} {
+			_, err := r.tx.ExecContext(slf.ctx, insert, item)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal(int64(2), meta.RowsAffected)
+}
+
+func (slf *StmtCache) TestQueryRow() {
+	const query = "SELECT name FROM users WHERE id = $1"
+
+	slf.mock.ExpectBegin()
+	stmt := slf.mock.ExpectPrepare(query)
+	stmt.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a"))
+	stmt.ExpectQuery().WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("b"))
+	slf.mock.ExpectCommit()
+
+	var names []string
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		for _, id := range []int{1, 2} {
+			var name string
+			err := r.tx.QueryRowContext(slf.ctx, query, id).Scan(&name)
+			if err != nil {
+				return err
+			}
+
+			names = append(names, name)
+		}
+
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal([]string{"a", "b"}, names)
+}
+
+func TestStmtCache(t *testing.T) {
+	suite.Run(t, new(StmtCache))
+}
@@ -0,0 +1,75 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// WithQueryTag prefixes every statement executed through the transaction with
+// a /* op=... */ comment, so slow statements can be attributed on the database side.
+//
+// The tag is taken from the statement context; an empty tag leaves the statement untouched.
+// Characters outside [A-Za-z0-9_.:-] are replaced with '_', so the tag can never close the comment.
+// Transaction control statements (BEGIN, COMMIT, ROLLBACK, SAVEPOINT, RELEASE) are never tagged.
+func WithQueryTag(tag func(ctx context.Context) string) Option {
+	return func(c *config) {
+		c.wrappers = append(c.wrappers, func(_ *txState, tx Transaction) Transaction {
+			return &taggedTx{Transaction: tx, tag: tag}
+		})
+	}
+}
+
+type taggedTx struct {
+	Transaction
+
+	tag func(ctx context.Context) string
+}
+
+func (slf *taggedTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return slf.Transaction.ExecContext(ctx, slf.apply(ctx, query), args...)
+}
+
+func (slf *taggedTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return slf.Transaction.QueryContext(ctx, slf.apply(ctx, query), args...)
+}
+
+func (slf *taggedTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return slf.Transaction.QueryRowContext(ctx, slf.apply(ctx, query), args...)
+}
+
+func (slf *taggedTx) apply(ctx context.Context, query string) string {
+	if isControlStatement(query) {
+		return query
+	}
+
+	op := sanitizeTag(slf.tag(ctx))
+	if op == "" {
+		return query
+	}
+
+	return "/* op=" + op + " */ " + query
+}
+
+func sanitizeTag(tag string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == '_', r == '.', r == ':', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, tag)
+}
+
+func isControlStatement(query string) bool {
+	head, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	switch strings.ToUpper(strings.TrimRight(head, ";")) {
+	case "BEGIN", "COMMIT", "ROLLBACK", "SAVEPOINT", "RELEASE", "END", "START":
+		return true
+	default:
+		return false
+	}
+}
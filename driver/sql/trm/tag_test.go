@@ -0,0 +1,102 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type opKey struct{}
+
+type QueryTag struct {
+	suite.Suite
+
+	ctx  context.Context
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *QueryTag) SetupTest() {
+	var err error
+	slf.db, slf.mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.impl = trm.New(slf.db, &txRepo{}, trm.WithQueryTag(func(ctx context.Context) string {
+		op, _ := ctx.Value(opKey{}).(string)
+		return op
+	}))
+}
+
+func (slf *QueryTag) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *QueryTag) TestTagged() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("/* op=CreateOrder */ INSERT INTO orders (item) VALUES ($1)").
+		WithArgs("item").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	slf.mock.ExpectQuery("/* op=CreateOrder */ SELECT 1").
+		WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+	slf.mock.ExpectCommit()
+
+	ctx := context.WithValue(slf.ctx, opKey{}, "CreateOrder")
+	err := slf.impl.InTx(ctx, func(r *txRepo) error {
+		_, err := r.tx.ExecContext(ctx, "INSERT INTO orders (item) VALUES ($1)", "item")
+		if err != nil {
+			return err
+		}
+
+		rows, err := r.tx.QueryContext(ctx, "SELECT 1")
+		if err != nil {
+			return err
+		}
+
+		return rows.Close()
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *QueryTag) TestInjectionSafe() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("/* op=x__DROP_TABLE_users__-- */ DELETE FROM orders").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit()
+
+	ctx := context.WithValue(slf.ctx, opKey{}, "x*/DROP TABLE users/*--")
+	err := slf.impl.InTx(ctx, func(r *txRepo) error {
+		_, err := r.tx.ExecContext(ctx, "DELETE FROM orders")
+		return err
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *QueryTag) TestSkipsControlAndEmpty() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SAVEPOINT sp1").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectExec("DELETE FROM orders").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit()
+
+	ctx := context.WithValue(slf.ctx, opKey{}, "op")
+	err := slf.impl.InTx(ctx, func(r *txRepo) error {
+		_, err := r.tx.ExecContext(ctx, "SAVEPOINT sp1")
+		if err != nil {
+			return err
+		}
+
+		_, err = r.tx.ExecContext(slf.ctx, "DELETE FROM orders")
+		return err
+	})
+	slf.Require().NoError(err)
+}
+
+func TestQueryTag(t *testing.T) {
+	suite.Run(t, new(QueryTag))
+}
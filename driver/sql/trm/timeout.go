@@ -0,0 +1,78 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var errNegativeTimeout = errors.New("negative timeout")
+
+// WithStatementTimeoutSQL issues SET LOCAL statement_timeout right after begin
+// when timeout returns true. Unlike a context deadline, the limit is enforced by PostgreSQL
+// and also stops queries that ignore cancellation. SET LOCAL scopes it to the transaction;
+// if it cannot be set, the transaction is rolled back.
+func WithStatementTimeoutSQL(timeout func(ctx context.Context) (time.Duration, bool)) Option {
+	return func(c *config) {
+		c.setup = append(c.setup, func(ctx context.Context, tx *sql.Tx) error {
+			d, ok := timeout(ctx)
+			if !ok {
+				return nil
+			}
+
+			err := setLocalTimeout(ctx, tx, "statement_timeout", d)
+			if err != nil {
+				return fmt.Errorf("set statement timeout: %w", err)
+			}
+
+			return nil
+		})
+	}
+}
+
+// WithDefaultTimeout bounds InTx calls whose context has no deadline to d, retries included,
+// as a safety net against transactions running unbounded. An existing deadline is kept as is,
+// whether it is tighter or looser than d; the Timeout call option takes precedence over both.
+func WithDefaultTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.defaultTimeout = d
+	}
+}
+
+// Timeout bounds a single InTxWith call to d instead of WithDefaultTimeout. Like any context
+// timeout, it only shortens a deadline the context already has, never extends it.
+func Timeout(d time.Duration) CallOption {
+	return func(c *call) {
+		c.timeout = d
+	}
+}
+
+// callContext returns the context an InTx call runs with.
+func (slf *config) callContext(ctx context.Context, c *call) (context.Context, context.CancelFunc) {
+	if c != nil && c.timeout > 0 {
+		return context.WithTimeout(ctx, c.timeout)
+	}
+
+	if _, ok := ctx.Deadline(); ok || slf.defaultTimeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, slf.defaultTimeout)
+}
+
+func setLocalTimeout(ctx context.Context, tx *sql.Tx, name string, d time.Duration) error {
+	if d < 0 {
+		return errNegativeTimeout
+	}
+
+	ms := d.Milliseconds()
+	if d%time.Millisecond != 0 {
+		ms++
+	}
+
+	_, err := tx.ExecContext(ctx, "SET LOCAL "+name+" = "+strconv.FormatInt(ms, 10))
+	return err
+}
@@ -0,0 +1,174 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type timeoutKey struct{}
+
+type StatementTimeout struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+}
+
+func (slf *StatementTimeout) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithStatementTimeoutSQL(func(ctx context.Context) (time.Duration, bool) {
+		d, ok := ctx.Value(timeoutKey{}).(time.Duration)
+		return d, ok
+	}))
+}
+
+func (slf *StatementTimeout) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *StatementTimeout) TestSet() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SET LOCAL statement_timeout = 1501").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectCommit()
+
+	ctx := context.WithValue(slf.ctx, timeoutKey{}, 1500*time.Millisecond+time.Microsecond)
+	err := slf.impl.InTx(ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func (slf *StatementTimeout) TestNotSet() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func (slf *StatementTimeout) TestSetFailureRollsBack() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SET LOCAL statement_timeout = 1000").WillReturnError(errors.New("err"))
+	slf.mock.ExpectRollback()
+
+	called := false
+	ctx := context.WithValue(slf.ctx, timeoutKey{}, time.Second)
+	err := slf.impl.InTx(ctx, func(*mockWithTx) error {
+		called = true
+		return nil
+	})
+	slf.Require().EqualError(err, "setup tx: set statement timeout: err")
+	slf.False(called)
+}
+
+func (slf *StatementTimeout) TestNegative() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	ctx := context.WithValue(slf.ctx, timeoutKey{}, -time.Second)
+	err := slf.impl.InTx(ctx, func(*mockWithTx) error { return nil })
+	slf.Require().EqualError(err, "setup tx: set statement timeout: negative timeout")
+}
+
+func TestStatementTimeout(t *testing.T) {
+	suite.Run(t, new(StatementTimeout))
+}
+
+type DefaultTimeout struct {
+	suite.Suite
+
+	mock     sqlmock.Sqlmock
+	impl     *trm.Impl[*mockWithTx]
+	deadline time.Time
+	ok       bool
+}
+
+func (slf *DefaultTimeout) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{},
+		trm.WithDefaultTimeout(time.Minute),
+		trm.WithBeforeCommit(func(ctx context.Context) error {
+			slf.deadline, slf.ok = ctx.Deadline()
+			return nil
+		}),
+	)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+}
+
+func (slf *DefaultTimeout) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *DefaultTimeout) TestNoDeadline() {
+	err := slf.impl.InTx(context.Background(), func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+
+	slf.Require().True(slf.ok)
+	slf.WithinDuration(time.Now().Add(time.Minute), slf.deadline, time.Second)
+}
+
+func (slf *DefaultTimeout) TestTighterDeadline() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	want, _ := ctx.Deadline()
+
+	err := slf.impl.InTx(ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+
+	slf.Require().True(slf.ok)
+	slf.Equal(want, slf.deadline)
+}
+
+func (slf *DefaultTimeout) TestLooserDeadline() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	want, _ := ctx.Deadline()
+
+	err := slf.impl.InTx(ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+
+	slf.Require().True(slf.ok)
+	slf.Equal(want, slf.deadline)
+}
+
+func (slf *DefaultTimeout) TestCallOverride() {
+	err := slf.impl.InTxWith(context.Background(), func(*mockWithTx) error { return nil }, trm.Timeout(time.Hour))
+	slf.Require().NoError(err)
+
+	slf.Require().True(slf.ok)
+	slf.WithinDuration(time.Now().Add(time.Hour), slf.deadline, time.Second)
+}
+
+func (slf *DefaultTimeout) TestCallOverrideNeverExtends() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	want, _ := ctx.Deadline()
+
+	err := slf.impl.InTxWith(ctx, func(*mockWithTx) error { return nil }, trm.Timeout(time.Hour))
+	slf.Require().NoError(err)
+
+	slf.Require().True(slf.ok)
+	slf.Equal(want, slf.deadline)
+}
+
+func TestDefaultTimeout(t *testing.T) {
+	suite.Run(t, new(DefaultTimeout))
+}
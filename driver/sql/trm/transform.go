@@ -0,0 +1,149 @@
+package trm
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrPrepareTransformed is returned by PrepareContext with WithArgTransformer, whose function
+// cannot see the arguments of prepared statements.
+var ErrPrepareTransformed = errors.New("trm: prepared statements bypass the argument transformer")
+
+// WithArgTransformer passes the arguments of every statement executed through the transaction
+// to fn, which returns the arguments actually sent, e.g. with flagged columns encrypted by
+// application-managed keys. Enforced by the transactor, it applies to every repository.
+//
+// An error fails the statement. QueryRowContext cannot carry it: the row fails as if its
+// context was cancelled, so no untransformed value is sent. The arguments of a *sql.Stmt cannot
+// be intercepted, so PrepareContext, and CopyFrom, fail with ErrPrepareTransformed; WithStmtCache
+// still works, as it prepares below the transformer.
+func WithArgTransformer(fn func(ctx context.Context, query string, args []any) ([]any, error)) Option {
+	return func(c *config) {
+		c.wrappers = append(c.wrappers, func(_ *txState, tx Transaction) Transaction {
+			return &argTx{Transaction: tx, transform: fn}
+		})
+	}
+}
+
+// WithRowTransformer registers fn to transform the values scanned into destinations wrapped
+// with Transformed, e.g. to decrypt columns encrypted by WithArgTransformer.
+//
+// database/sql does not let a transaction intercept Rows.Scan, so the columns to transform
+// are chosen at the scan site: rows.Scan(&id, trm.Transformed(ctx, &email)).
+func WithRowTransformer(fn func(ctx context.Context, src any) (any, error)) Option {
+	return func(c *config) {
+		c.rowTransform = fn
+	}
+}
+
+// Transformed wraps dest, a sql.Scanner or a pointer to string, []byte or any, so the value
+// scanned into it first goes through the WithRowTransformer function of the transaction
+// carried by ctx. Scanning fails with ErrNoTransaction outside a transaction, so a value
+// is never silently left untransformed.
+func Transformed(ctx context.Context, dest any) sql.Scanner {
+	return &transformedScanner{ctx: ctx, dest: dest}
+}
+
+type argTx struct {
+	Transaction
+
+	transform func(ctx context.Context, query string, args []any) ([]any, error)
+}
+
+func (slf *argTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	args, err := slf.transform(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("transform args: %w", err)
+	}
+
+	return slf.Transaction.ExecContext(ctx, query, args...)
+}
+
+func (slf *argTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	args, err := slf.transform(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("transform args: %w", err)
+	}
+
+	return slf.Transaction.QueryContext(ctx, query, args...)
+}
+
+func (slf *argTx) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, ErrPrepareTransformed
+}
+
+func (slf *argTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	args, err := slf.transform(ctx, query, args)
+	if err != nil {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		return slf.Transaction.QueryRowContext(cancelled, query)
+	}
+
+	return slf.Transaction.QueryRowContext(ctx, query, args...)
+}
+
+type transformedScanner struct {
+	ctx  context.Context
+	dest any
+}
+
+func (slf *transformedScanner) Scan(src any) error {
+	st := stateFrom(slf.ctx)
+	if st == nil {
+		return ErrNoTransaction
+	}
+
+	v := src
+	if st.cfg.rowTransform != nil {
+		var err error
+
+		v, err = st.cfg.rowTransform(slf.ctx, src)
+		if err != nil {
+			return fmt.Errorf("transform row: %w", err)
+		}
+	}
+
+	return assign(slf.dest, v)
+}
+
+// assign stores v into dest, supporting the destinations documented by Transformed.
+func assign(dest, v any) error {
+	switch d := dest.(type) {
+	case sql.Scanner:
+		return d.Scan(v)
+	case *any:
+		// A []byte src is owned by the driver and only valid until the next scan.
+		if b, ok := v.([]byte); ok {
+			v = bytes.Clone(b)
+		}
+
+		*d = v
+
+		return nil
+	case *string:
+		switch s := v.(type) {
+		case string:
+			*d = s
+			return nil
+		case []byte:
+			*d = string(s)
+			return nil
+		}
+	case *[]byte:
+		switch s := v.(type) {
+		case []byte:
+			*d = append([]byte(nil), s...)
+			return nil
+		case string:
+			*d = []byte(s)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("trm: cannot scan %T into %T", v, dest)
+}
@@ -0,0 +1,146 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+// rot13 stands in for a cipher.
+func rot13(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return 'a' + (r-'a'+13)%26
+		case r >= 'A' && r <= 'Z':
+			return 'A' + (r-'A'+13)%26
+		}
+
+		return r
+	}, s)
+}
+
+type Transform struct {
+	suite.Suite
+
+	ctx  context.Context
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *Transform) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.db = db
+	slf.mock = mock
+	slf.impl = trm.New(db, &txRepo{},
+		trm.WithArgTransformer(func(_ context.Context, query string, args []any) ([]any, error) {
+			if !strings.Contains(query, "email = $1") {
+				return args, nil
+			}
+
+			if s, ok := args[0].(string); ok {
+				return append([]any{rot13(s)}, args[1:]...), nil
+			}
+
+			return nil, errors.New("email must be a string")
+		}),
+		trm.WithRowTransformer(func(_ context.Context, src any) (any, error) {
+			return rot13(string(src.([]byte))), nil
+		}),
+	)
+}
+
+func (slf *Transform) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *Transform) TestRoundTrip() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("UPDATE users SET email = $1 WHERE id = $2").
+		WithArgs("nyvpr@rknzcyr.pbz", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectQuery("SELECT id, email FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, []byte("nyvpr@rknzcyr.pbz")))
+	slf.mock.ExpectCommit()
+
+	var (
+		id    int
+		email string
+	)
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, r *txRepo) error {
+		_, err := r.tx.ExecContext(ctx, "UPDATE users SET email = $1 WHERE id = $2", "alice@example.com", 1)
+		slf.Require().NoError(err)
+
+		return r.tx.QueryRowContext(ctx, "SELECT id, email FROM users").Scan(&id, trm.Transformed(ctx, &email))
+	})
+	slf.Require().NoError(err)
+	slf.Equal(1, id)
+	slf.Equal("alice@example.com", email)
+}
+
+func (slf *Transform) TestArgError() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, r *txRepo) error {
+		var id int
+		errRow := r.tx.QueryRowContext(ctx, "SELECT id FROM users WHERE email = $1", 42).Scan(&id)
+		slf.Require().ErrorIs(errRow, context.Canceled)
+
+		_, err := r.tx.ExecContext(ctx, "UPDATE users SET email = $1", 42)
+
+		return err
+	})
+	slf.Require().EqualError(err, "trm callback: transform args: email must be a string")
+}
+
+func (slf *Transform) TestPrepareRejected() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, r *txRepo) error {
+		_, err := trm.CopyFrom(ctx, r.tx, "users", []string{"email"}, [][]any{{"alice@example.com"}})
+		return err
+	})
+	slf.Require().ErrorIs(err, trm.ErrPrepareTransformed)
+}
+
+func (slf *Transform) TestScanAnyClonesBytes() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	src := []byte("alice")
+	impl := trm.New(slf.db, &txRepo{})
+
+	var v any
+
+	err := impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		return trm.Transformed(ctx, &v).Scan(src)
+	})
+	slf.Require().NoError(err)
+
+	src[0] = 'X'
+	slf.Equal([]byte("alice"), v)
+}
+
+func (slf *Transform) TestOutsideTransaction() {
+	var email string
+	err := trm.Transformed(slf.ctx, &email).Scan([]byte("nyvpr"))
+	slf.Require().ErrorIs(err, trm.ErrNoTransaction)
+}
+
+func TestTransform(t *testing.T) {
+	suite.Run(t, new(Transform))
+}
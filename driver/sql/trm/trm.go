@@ -3,19 +3,26 @@ package trm
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"sync/atomic"
+	"time"
 )
 
 type impl[T any] struct {
-	db *sql.DB
-	wt withTx[T]
+	db          *sql.DB
+	wt          withTx[T]
+	cfg         *config
+	stats       stats
+	maintenance atomic.Bool
 }
 
 //nolint:revive // exported constructor intentionally returns hidden implementation type
-func New[T withTx[T]](db *sql.DB, wt T) *impl[T] {
+func New[T withTx[T]](db *sql.DB, wt T, opts ...Option) *impl[T] {
 	return &impl[T]{
-		db: db,
-		wt: wt,
+		db:  db,
+		wt:  wt,
+		cfg: newConfig(opts),
 	}
 }
 
@@ -23,25 +30,390 @@ func (slf *impl[T]) InTx(
 	ctx context.Context,
 	fn func(repo T) error,
 ) error {
-	tx, err := slf.db.BeginTx(ctx, nil)
+	return slf.InTxCtx(ctx, func(_ context.Context, repo T) error {
+		return fn(repo)
+	})
+}
+
+// InTxCtx is InTx for callbacks that need the transaction context, e.g. to open a Savepoint.
+//
+// The context is that of the current attempt: under retries it carries the deadline of
+// WithMaxTxDuration and the Attempt number of this attempt, while a context an InTx callback
+// closes over stays the one passed to InTx. Pass it to the queries of the callback.
+func (slf *impl[T]) InTxCtx(
+	ctx context.Context,
+	fn func(ctx context.Context, repo T) error,
+) error {
+	_, err := slf.run(ctx, nil, fn)
+	return err
+}
+
+// InTxWith is InTx with per-call options, which take precedence over context and New-level options.
+func (slf *impl[T]) InTxWith(
+	ctx context.Context,
+	fn func(repo T) error,
+	opts ...CallOption,
+) error {
+	_, err := slf.run(ctx, newCall(opts), func(_ context.Context, repo T) error {
+		return fn(repo)
+	})
+
+	return err
+}
+
+// InTxMeta is InTx that also reports what happened in the final attempt of the transaction.
+func (slf *impl[T]) InTxMeta(
+	ctx context.Context,
+	fn func(repo T) error,
+) (TxMeta, error) {
+	st, err := slf.run(ctx, nil, func(_ context.Context, repo T) error {
+		return fn(repo)
+	})
+
+	return st.meta(), err
+}
+
+// InTxAttempts is InTx that also returns the number of attempts made, 1 unless a failure was retried,
+// e.g. for a debugging response header. Begin failures count as attempts, as in EventRetry;
+// it is 0 when InTx failed before beginning, e.g. with ErrNestedTx.
+func (slf *impl[T]) InTxAttempts(
+	ctx context.Context,
+	fn func(repo T) error,
+) (int, error) {
+	c := &call{}
+	_, err := slf.run(ctx, c, func(_ context.Context, repo T) error {
+		return fn(repo)
+	})
+
+	return c.attempts, err
+}
+
+// InTxExtra is InTx that also calls extra with the transaction the repository is bound to,
+// before fn, to build one-off repositories sharing that transaction without extending T.
+func (slf *impl[T]) InTxExtra(
+	ctx context.Context,
+	extra func(tx Transaction),
+	fn func(repo T) error,
+) error {
+	_, err := slf.run(ctx, nil, func(ctx context.Context, repo T) error {
+		extra(stateFrom(ctx).txn)
+		return fn(repo)
+	})
+
+	return err
+}
+
+func (slf *impl[T]) run(
+	ctx context.Context,
+	c *call,
+	fn func(ctx context.Context, repo T) error,
+) (*txState, error) {
+	if c != nil && c.ack != nil {
+		return slf.runAcked(ctx, c, fn)
+	}
+
+	db, err := slf.resolveDB(ctx)
 	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
+		return nil, err
 	}
+
+	return slf.runOn(ctx, db, c, fn)
+}
+
+// runOn runs fn in transactions begun on db, retrying as configured.
+func (slf *impl[T]) runOn(
+	ctx context.Context,
+	db beginner,
+	c *call,
+	fn func(ctx context.Context, repo T) error,
+) (*txState, error) {
+	err := slf.checkNesting(stateFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	err = slf.checkMaintenance(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBudget(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := slf.cfg.callContext(slf.cfg.withCaller(ctx), c)
+	defer cancel()
+
+	for attempt := 1; ; attempt++ {
+		if c != nil {
+			c.attempts = attempt
+		}
+
+		var (
+			dry    *txState
+			dryErr error
+		)
+		if attempt == 1 && slf.cfg.dryRuns(c) {
+			dry, dryErr = slf.attempt(ctx, db, c, fn, attempt, true)
+		}
+
+		st, err := slf.attempt(ctx, db, c, fn, attempt, false)
+		slf.cfg.explain.explain(ctx, db, st)
+
+		if dry != nil {
+			slf.cfg.checkDeterminism(ctx, dry, st, dryErr, err)
+		}
+
+		if err == nil || c != nil && c.noRetry || !slf.retry(ctx, attempt, st, err) {
+			return st, slf.cfg.mapErr(ctx, slf.cfg.normalize(markReadOnly(st.withReplay(err))))
+		}
+
+		if slf.cfg.sink != nil {
+			slf.cfg.emit(ctx, st, Event{
+				Kind:    EventRetry,
+				Err:     err,
+				Class:   slf.cfg.classify(err),
+				Attempt: attempt,
+			})
+		}
+	}
+}
+
+func (slf *impl[T]) attempt(
+	ctx context.Context,
+	db beginner,
+	c *call,
+	fn func(ctx context.Context, repo T) error,
+	n int,
+	dryRun bool,
+) (*txState, error) {
+	opts := slf.cfg.txOptions(ctx, c)
+
+	txCtx, cancelDeadline := slf.cfg.txDeadline(ctx)
+	defer cancelDeadline()
+
+	beginCtx, cancelTx := slf.cfg.beginContext(txCtx)
+	defer cancelTx()
+
+	st := &txState{binder: slf, cfg: slf.cfg, stats: &slf.stats, attempt: n, sampled: slf.cfg.sample(ctx)}
+	if dryRun {
+		// The dry run of WithDeterminismCheck is kept out of Stats and events.
+		st.dryRun = true
+		st.stats = &stats{}
+	}
+
+	if c != nil {
+		st.cleanups = c.cleanups
+		st.lockTables = c.lockTables
+		st.guarded = c.guard
+	}
+
+	defer st.detached.end()
+
+	var err error
+
 	defer func() {
-		_ = tx.Rollback()
+		if !st.begun() {
+			return
+		}
+
+		if !st.committed {
+			if st.rollbackReason == RollbackNone {
+				// Every failure records its reason before returning: this is a panic unwinding.
+				st.rollbackReason = RollbackPanic
+			}
+
+			slf.rollback(ctx, st, err)
+		}
+
+		st.stats.inFlight.Add(-1)
 	}()
 
-	err = fn(slf.wt.WithTx(tx))
+	err = slf.start(txCtx, beginCtx, db, opts, st)
+	if err != nil {
+		st.failed(txCtx, RollbackSetupFailed)
+		if st.tx == nil {
+			// Nothing to roll back: a begin failure is reported without state.
+			return nil, err
+		}
+
+		return st, err
+	}
+
+	err = slf.complete(ctx, txCtx, st, cancelTx, fn)
+
+	return st, err
+}
+
+// complete runs fn in the begun transaction of st and commits it.
+func (slf *impl[T]) complete(
+	ctx, txCtx context.Context,
+	st *txState,
+	cancelTx context.CancelFunc,
+	fn func(ctx context.Context, repo T) error,
+) error {
+	// One context carries st for the callback and the pre-commit steps: the hot path allocates it once.
+	stCtx := withState(txCtx, st)
+
+	err := fn(stCtx, slf.wt.WithTx(st.txn))
+	if errBegin := st.beginErr(); errBegin != nil {
+		st.failed(txCtx, RollbackSetupFailed)
+		return errBegin
+	}
+
+	if err != nil {
+		st.failed(txCtx, RollbackError)
+		return callbackError(err)
+	}
+
+	err = slf.cfg.prepareCommit(stCtx, st)
+	if errBegin := st.beginErr(); errBegin != nil {
+		// A DeferInTx function or the before-commit hook may be the first to touch the database.
+		st.failed(txCtx, RollbackSetupFailed)
+		return errBegin
+	}
+
+	if err != nil {
+		st.failed(txCtx, RollbackVetoed)
+		return err
+	}
+
+	if st.dryRun {
+		st.rollbackReason = RollbackVetoed
+		return errDryRun
+	}
+
+	err = slf.cfg.commit(txCtx, st, cancelTx)
+	if err != nil {
+		st.failed(txCtx, RollbackCommitFailed)
+		err = fmt.Errorf("commit tx: %w", markDeferredConstraint(err))
+		if slf.cfg.panicOnCommit {
+			panic(err)
+		}
+
+		return err
+	}
+
+	st.committed = true
+	if st.begun() {
+		st.stats.commits.Add(1)
+		slf.cfg.emit(ctx, st, Event{
+			Kind:        EventCommit,
+			LockWait:    st.lockWait.finish(),
+			RowsScanned: st.rowsScanned.Load(),
+		})
+	}
+
+	if len(st.onCommit) == 0 {
+		return nil
+	}
+
+	err = st.runOnCommit(withState(ctx, st))
 	if err != nil {
-		return fmt.Errorf("trm callback: %w", err)
+		return slf.cfg.onCommitFailed(ctx, err)
+	}
+
+	return nil
+}
+
+// start begins the transaction of st, or arranges for WithLazyBegin to begin it on first use.
+func (slf *impl[T]) start(
+	ctx, beginCtx context.Context,
+	db beginner,
+	opts *sql.TxOptions,
+	st *txState,
+) error {
+	st.opts = opts
+	if slf.startExternal(ctx, st) {
+		return nil
 	}
 
-	err = tx.Commit()
+	if slf.cfg.lazyBegin {
+		begin := func() error {
+			return slf.begin(ctx, beginCtx, db, opts, st)
+		}
+		st.lazy = &lazyBegin{begin: begin, db: db}
+		st.txn = slf.cfg.wrap(st, &lazyTx{st: st})
+
+		return nil
+	}
+
+	err := slf.begin(ctx, beginCtx, db, opts, st)
 	if err != nil {
-		return fmt.Errorf("commit tx: %w", err)
+		return err
 	}
 
+	st.txn = slf.cfg.wrap(st, st.tx)
+
 	return nil
 }
 
-var _ Transaction = (*sql.Tx)(nil)
+// begin begins the transaction of st and runs the setup options on it.
+func (slf *impl[T]) begin(
+	ctx, beginCtx context.Context,
+	db beginner,
+	opts *sql.TxOptions,
+	st *txState,
+) error {
+	tx, err := slf.cfg.beginTx(beginCtx, db, opts)
+	slf.cfg.emit(ctx, st, Event{Kind: EventBegin, Err: err, TxOptions: opts})
+	if err != nil {
+		st.stats.beginFailures.Add(1)
+		return fmt.Errorf("begin tx: %w", &BeginError{Err: err})
+	}
+
+	st.stats.inFlight.Add(1)
+	st.tx = tx
+	st.startTime = time.Now()
+
+	if len(slf.cfg.setup) > 0 {
+		err = slf.cfg.setupTx(withState(ctx, st), tx)
+		if err != nil {
+			return fmt.Errorf("setup tx: %w", err)
+		}
+	}
+
+	err = lockTables(ctx, tx, st.lockTables)
+	if err != nil {
+		return fmt.Errorf("setup tx: %w", err)
+	}
+
+	return slf.cfg.startLockWait(ctx, db, st)
+}
+
+func (slf *impl[T]) rollback(ctx context.Context, st *txState, cause error) {
+	cause = slf.cfg.rollbackTx(st.rollbackTarget(), cause)
+	st.stats.rollbacks.Add(1)
+	lockWait := st.lockWait.finish()
+
+	if slf.cfg.sink == nil {
+		return
+	}
+
+	slf.cfg.emit(slf.cfg.rollbackCtx(ctx), st, Event{
+		Kind:        EventRollback,
+		Err:         cause,
+		Class:       slf.cfg.classify(cause),
+		LockWait:    lockWait,
+		Reason:      st.rollbackReason,
+		RowsScanned: st.rowsScanned.Load(),
+	})
+}
+
+func (slf *impl[T]) bind(tx Transaction) any {
+	return slf.wt.WithTx(tx)
+}
+
+func (slf *impl[T]) retry(ctx context.Context, attempt int, st *txState, err error) bool {
+	if ctx.Err() != nil || st != nil && st.committed {
+		return false
+	}
+
+	if st == nil || st.beginErr() != nil {
+		var errBegin *BeginError
+		return slf.cfg.onBeginFailure != nil && errors.As(err, &errBegin) && slf.cfg.onBeginFailure(ctx, errBegin)
+	}
+
+	return slf.cfg.rollbackDecider != nil && slf.cfg.retryEnabled(ctx) && slf.cfg.rollbackDecider(ctx, attempt, err)
+}
@@ -27,6 +27,14 @@ func (m *mockWithTx) WithTx(_ trm.Transaction) *mockWithTx {
 	return m
 }
 
+type txRepo struct {
+	tx trm.Transaction
+}
+
+func (r *txRepo) WithTx(tx trm.Transaction) *txRepo {
+	return &txRepo{tx: tx}
+}
+
 func (slf *InTx) SetupTest() {
 	var err error
 	slf.db, slf.mock, err = sqlmock.New()
@@ -0,0 +1,23 @@
+package trm
+
+import "database/sql"
+
+type txFunc[T any] func(tx *sql.Tx) T
+
+func (slf txFunc[T]) WithTx(tx Transaction) T {
+	return slf(tx.(*sql.Tx)) //nolint:errcheck // FromTxFunc accepts no options, the transaction is never wrapped
+}
+
+// FromTxFunc builds a transactor from existing bind logic of the form func(*sql.Tx) T,
+// for codebases that have not moved their repositories to WithTx adapters yet.
+//
+// It accepts no options: bind needs the raw *sql.Tx, which transaction wrappers would hide.
+//
+//nolint:revive // exported constructor intentionally returns hidden implementation type
+func FromTxFunc[T any](db *sql.DB, bind func(tx *sql.Tx) T) *impl[T] {
+	return &impl[T]{
+		db:  db,
+		wt:  txFunc[T](bind),
+		cfg: newConfig(nil),
+	}
+}
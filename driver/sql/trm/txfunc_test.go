@@ -0,0 +1,68 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+	"github.com/metalfm/transactor/tr"
+)
+
+type legacyRepo struct {
+	tx *sql.Tx
+}
+
+type FromTxFunc struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	tr   tr.Transactor[*legacyRepo]
+}
+
+func (slf *FromTxFunc) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.tr = trm.FromTxFunc(db, func(tx *sql.Tx) *legacyRepo {
+		return &legacyRepo{tx: tx}
+	})
+}
+
+func (slf *FromTxFunc) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *FromTxFunc) TestCommit() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("INSERT INTO users").WillReturnResult(sqlmock.NewResult(1, 1))
+	slf.mock.ExpectCommit()
+
+	err := slf.tr.InTx(slf.ctx, func(r *legacyRepo) error {
+		_, err := r.tx.ExecContext(slf.ctx, "INSERT INTO users")
+		return err
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *FromTxFunc) TestRollback() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+
+	err := slf.tr.InTx(slf.ctx, func(r *legacyRepo) error {
+		slf.NotNil(r.tx)
+		return errors.New("err")
+	})
+	slf.Require().EqualError(err, "trm callback: err")
+}
+
+func TestFromTxFunc(t *testing.T) {
+	suite.Run(t, new(FromTxFunc))
+}
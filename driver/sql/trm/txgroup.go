@@ -0,0 +1,71 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// TxGroup returns a SerialTx over the transaction carried by ctx, for callbacks that fan out
+// across goroutines, e.g. with errgroup and DetachContext: each statement made through it holds
+// the connection of the transaction alone, so goroutines never interleave on it.
+//
+// Only statements made through the same SerialTx are serialized: share one among the goroutines,
+// and do not use the repository or RawTx from them at the same time.
+func TxGroup(ctx context.Context) (*SerialTx, error) {
+	st := stateFrom(ctx)
+	if st == nil || st.committed {
+		return nil, fmt.Errorf("tx group: %w", ErrNoTransaction)
+	}
+
+	return &SerialTx{tx: st.txn}, nil
+}
+
+// SerialTx serializes the statements of concurrent goroutines on one transaction, see TxGroup.
+// It is safe for concurrent use.
+type SerialTx struct {
+	mu sync.Mutex
+	tx Transaction
+}
+
+// ExecContext is Transaction.ExecContext, run once no other statement of the SerialTx runs.
+func (slf *SerialTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	return slf.tx.ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs query once no other statement of the SerialTx runs and passes its rows to scan, keeping
+// the connection until scan returned and the rows are closed: unlike Transaction.QueryContext it
+// cannot return *sql.Rows, as reading them later would interleave with other statements.
+// It returns the first error of the query, scan, iteration or closing the rows.
+func (slf *SerialTx) QueryContext(
+	ctx context.Context,
+	scan func(rows *sql.Rows) error,
+	query string,
+	args ...any,
+) error {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	rows, err := slf.tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	err = scan(rows)
+	if err != nil {
+		_ = rows.Close()
+		return err
+	}
+
+	err = rows.Err()
+	if err != nil {
+		_ = rows.Close()
+		return err
+	}
+
+	return rows.Close()
+}
@@ -0,0 +1,101 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type TxGroup struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+}
+
+func (slf *TxGroup) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{})
+}
+
+func (slf *TxGroup) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *TxGroup) TestSerializesStatements() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectQuery("SELECT id FROM orders").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	slf.mock.ExpectExec("UPDATE stock SET qty = qty - 1").WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectCommit()
+
+	var (
+		mu    sync.Mutex
+		steps []string
+	)
+	step := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		steps = append(steps, name)
+	}
+
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *mockWithTx) error {
+		g, err := trm.TxGroup(ctx)
+		if err != nil {
+			return err
+		}
+
+		var wg sync.WaitGroup
+		var ids []int
+
+		err = g.QueryContext(ctx, func(rows *sql.Rows) error {
+			wg.Go(func() {
+				_, errExec := g.ExecContext(ctx, "UPDATE stock SET qty = qty - 1")
+				slf.NoError(errExec)
+				step("exec")
+			})
+
+			// The exec must wait for the rows, however long reading them takes.
+			time.Sleep(20 * time.Millisecond)
+
+			for rows.Next() {
+				var id int
+				if errScan := rows.Scan(&id); errScan != nil {
+					return errScan
+				}
+
+				ids = append(ids, id)
+			}
+			step("scan")
+
+			return nil
+		}, "SELECT id FROM orders")
+		wg.Wait()
+
+		slf.Equal([]int{1, 2}, ids)
+
+		return err
+	})
+	slf.Require().NoError(err)
+	slf.Equal([]string{"scan", "exec"}, steps)
+}
+
+func (slf *TxGroup) TestOutsideTransaction() {
+	_, err := trm.TxGroup(slf.ctx)
+	slf.Require().ErrorIs(err, trm.ErrNoTransaction)
+}
+
+func TestTxGroup(t *testing.T) {
+	suite.Run(t, new(TxGroup))
+}
@@ -0,0 +1,70 @@
+package trm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// WithIDGenerator replaces the random UUID identifying each transaction, see TxID.
+func WithIDGenerator(gen func() string) Option {
+	return func(c *config) {
+		c.newID = gen
+	}
+}
+
+// TxID returns the identifier of the transaction carried by ctx, or an empty string outside a transaction.
+// The same identifier is reported in events and embedded in savepoint names, correlating every log line
+// of one transaction. It is generated on first use, so transactions that never ask for it pay nothing.
+func TxID(ctx context.Context) string {
+	st := stateFrom(ctx)
+	if st == nil {
+		return ""
+	}
+
+	return st.id()
+}
+
+// id returns the transaction identifier, generating it on first use.
+func (slf *txState) id() string {
+	slf.idOnce.Do(func() {
+		slf.txID = slf.cfg.newID()
+	})
+
+	return slf.txID
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+
+	return string(buf[:])
+}
+
+// identifierPart maps id to the characters allowed in an unquoted SQL identifier.
+func identifierPart(id string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, id)
+}
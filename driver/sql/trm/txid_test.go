@@ -0,0 +1,81 @@
+package trm_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type TxID struct {
+	suite.Suite
+
+	ctx    context.Context
+	mock   sqlmock.Sqlmock
+	impl   *trm.Impl[*txRepo]
+	events []trm.Event
+}
+
+func (slf *TxID) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.events = nil
+	slf.impl = trm.New(db, &txRepo{}, trm.WithEventSink(func(_ context.Context, e trm.Event) {
+		slf.events = append(slf.events, e)
+	}))
+}
+
+func (slf *TxID) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *TxID) TestDefaultUUIDInEvents() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	var id string
+	err := slf.impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		id = trm.TxID(ctx)
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Regexp(regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), id)
+
+	slf.Require().Len(slf.events, 2)
+	slf.Equal(id, slf.events[0].TxID)
+	slf.Equal(id, slf.events[1].TxID)
+}
+
+func (slf *TxID) TestGeneratorAndSavepointPrefix() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	impl := trm.New(db, &txRepo{}, trm.WithIDGenerator(func() string { return "01J-X" }))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_1_01J_X").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT sp_1_01J_X").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = impl.InTxCtx(slf.ctx, func(ctx context.Context, _ *txRepo) error {
+		slf.Equal("01J-X", trm.TxID(ctx))
+		return trm.NewSavepoint[*txRepo](ctx).Run(func(*txRepo) error { return nil })
+	})
+	slf.Require().NoError(err)
+	slf.Require().NoError(mock.ExpectationsWereMet())
+}
+
+func (slf *TxID) TestOutsideTransaction() {
+	slf.Empty(trm.TxID(slf.ctx))
+}
+
+func TestTxID(t *testing.T) {
+	suite.Run(t, new(TxID))
+}
@@ -0,0 +1,36 @@
+package trm
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type unitOfWorkKey struct{}
+
+type unitOfWork struct {
+	id    string
+	ended atomic.Bool
+}
+
+// WithUnitOfWork starts a unit of work: the transactions started with the returned context,
+// by any transactor, share its random identifier, reported in Event.UnitOfWork, so separate
+// transactions of one business operation (e.g. reserve, then confirm) can be correlated.
+// Call end when the operation is over; transactions started afterwards are not tagged.
+func WithUnitOfWork(ctx context.Context) (context.Context, func()) {
+	uow := &unitOfWork{id: newUUID()}
+
+	return context.WithValue(ctx, unitOfWorkKey{}, uow), func() {
+		uow.ended.Store(true)
+	}
+}
+
+// UnitOfWork returns the identifier of the unit of work carried by ctx,
+// or an empty string outside a unit of work or after it ended.
+func UnitOfWork(ctx context.Context) string {
+	uow, _ := ctx.Value(unitOfWorkKey{}).(*unitOfWork)
+	if uow == nil || uow.ended.Load() {
+		return ""
+	}
+
+	return uow.id
+}
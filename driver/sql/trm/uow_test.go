@@ -0,0 +1,74 @@
+package trm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type UnitOfWork struct {
+	suite.Suite
+
+	mock   sqlmock.Sqlmock
+	impl   *trm.Impl[*mockWithTx]
+	events []trm.Event
+}
+
+func (slf *UnitOfWork) SetupTest() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	slf.mock = mock
+	slf.events = nil
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithEventSink(func(_ context.Context, e trm.Event) {
+		if e.Kind == trm.EventCommit {
+			slf.events = append(slf.events, e)
+		}
+	}))
+}
+
+func (slf *UnitOfWork) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *UnitOfWork) inTx(ctx context.Context) {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+	slf.Require().NoError(slf.impl.InTx(ctx, func(*mockWithTx) error { return nil }))
+}
+
+func (slf *UnitOfWork) TestSharedID() {
+	ctx, end := trm.WithUnitOfWork(context.Background())
+	slf.NotEmpty(trm.UnitOfWork(ctx))
+
+	slf.inTx(ctx)
+	slf.inTx(ctx)
+	end()
+	slf.inTx(ctx)
+	slf.inTx(context.Background())
+
+	slf.Require().Len(slf.events, 4)
+	slf.NotEmpty(slf.events[0].UnitOfWork)
+	slf.Equal(slf.events[0].UnitOfWork, slf.events[1].UnitOfWork)
+	slf.NotEqual(slf.events[0].TxID, slf.events[1].TxID)
+	slf.Empty(slf.events[2].UnitOfWork)
+	slf.Empty(slf.events[3].UnitOfWork)
+}
+
+func (slf *UnitOfWork) TestDistinctUnits() {
+	first, end := trm.WithUnitOfWork(context.Background())
+	defer end()
+
+	second, endSecond := trm.WithUnitOfWork(context.Background())
+	defer endSecond()
+
+	slf.NotEqual(trm.UnitOfWork(first), trm.UnitOfWork(second))
+}
+
+func TestUnitOfWork(t *testing.T) {
+	suite.Run(t, new(UnitOfWork))
+}
@@ -0,0 +1,32 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// WithSessionVars sets the variables returned by vars right after begin, scoped to the transaction,
+// e.g. rls.user_id read by PostgreSQL row-level security policies. If any of them cannot be set,
+// the transaction is rolled back.
+//
+// Each variable is set with set_config(name, value, true), the function form of SET LOCAL:
+// name and value are bind parameters, so no quoting is involved and a value cannot inject SQL.
+// Variables are set in name order.
+func WithSessionVars(vars func(ctx context.Context) map[string]string) Option {
+	return func(c *config) {
+		c.setup = append(c.setup, func(ctx context.Context, tx *sql.Tx) error {
+			m := vars(ctx)
+			for _, name := range slices.Sorted(maps.Keys(m)) {
+				_, err := tx.ExecContext(ctx, "SELECT set_config($1, $2, true)", name, m[name])
+				if err != nil {
+					return fmt.Errorf("set %s: %w", name, err)
+				}
+			}
+
+			return nil
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type userKey struct{}
+
+type SessionVars struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*mockWithTx]
+}
+
+func (slf *SessionVars) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.WithValue(context.Background(), userKey{}, "42'; DROP TABLE users; --")
+	slf.mock = mock
+	slf.impl = trm.New(db, &mockWithTx{}, trm.WithSessionVars(func(ctx context.Context) map[string]string {
+		user, ok := ctx.Value(userKey{}).(string)
+		if !ok {
+			return nil
+		}
+
+		return map[string]string{"rls.user_id": user, "rls.tenant": "acme"}
+	}))
+}
+
+func (slf *SessionVars) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *SessionVars) TestSetInNameOrder() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SELECT set_config($1, $2, true)").WithArgs("rls.tenant", "acme").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectExec("SELECT set_config($1, $2, true)").WithArgs("rls.user_id", "42'; DROP TABLE users; --").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func (slf *SessionVars) TestNone() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(context.Background(), func(*mockWithTx) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func (slf *SessionVars) TestFailureRollsBack() {
+	expected := errors.New("unrecognized configuration parameter")
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("SELECT set_config($1, $2, true)").WithArgs("rls.tenant", "acme").WillReturnError(expected)
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(slf.ctx, func(*mockWithTx) error {
+		slf.Fail("callback must not run")
+		return nil
+	})
+	slf.Require().ErrorIs(err, expected)
+}
+
+func TestSessionVars(t *testing.T) {
+	suite.Run(t, new(SessionVars))
+}
@@ -0,0 +1,38 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// WithStartupCheck registers check to be run by Verify, e.g. to reject a database whose schema
+// is older than the code expects:
+//
+//	trm.WithStartupCheck(func(ctx context.Context, db *sql.DB) error {
+//		var version int
+//		err := db.QueryRowContext(ctx, "SELECT max(version) FROM schema_migrations").Scan(&version)
+//		if err == nil && version < minVersion {
+//			err = fmt.Errorf("schema version %d, want at least %d", version, minVersion)
+//		}
+//		return err
+//	})
+func WithStartupCheck(check func(ctx context.Context, db *sql.DB) error) Option {
+	return func(c *config) {
+		c.startupChecks = append(c.startupChecks, check)
+	}
+}
+
+// Verify runs the WithStartupCheck checks in order against the database passed to New and
+// returns the first failure, so an application can refuse to start against a misconfigured
+// or unmigrated database. New does not run them itself: it has no context and cannot fail.
+func (slf *impl[T]) Verify(ctx context.Context) error {
+	for _, check := range slf.cfg.startupChecks {
+		err := check(ctx, slf.db)
+		if err != nil {
+			return fmt.Errorf("startup check: %w", err)
+		}
+	}
+
+	return nil
+}
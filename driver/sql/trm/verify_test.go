@@ -0,0 +1,88 @@
+package trm_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+var errOldSchema = errors.New("schema too old")
+
+type Verify struct {
+	suite.Suite
+
+	ctx  context.Context
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+}
+
+func (slf *Verify) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.db = db
+	slf.mock = mock
+}
+
+func (slf *Verify) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func minSchemaVersion(want int) func(ctx context.Context, db *sql.DB) error {
+	return func(ctx context.Context, db *sql.DB) error {
+		var version int
+
+		err := db.QueryRowContext(ctx, "SELECT max(version) FROM schema_migrations").Scan(&version)
+		if err != nil {
+			return err
+		}
+
+		if version < want {
+			return errOldSchema
+		}
+
+		return nil
+	}
+}
+
+func (slf *Verify) TestPass() {
+	slf.mock.ExpectQuery("SELECT max(version) FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(7))
+
+	impl := trm.New(slf.db, &mockWithTx{}, trm.WithStartupCheck(minSchemaVersion(7)))
+	slf.Require().NoError(impl.Verify(slf.ctx))
+}
+
+func (slf *Verify) TestFailStopsAtFirst() {
+	slf.mock.ExpectQuery("SELECT max(version) FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(6))
+
+	called := false
+	impl := trm.New(slf.db, &mockWithTx{},
+		trm.WithStartupCheck(minSchemaVersion(7)),
+		trm.WithStartupCheck(func(context.Context, *sql.DB) error {
+			called = true
+			return nil
+		}),
+	)
+
+	err := impl.Verify(slf.ctx)
+	slf.Require().ErrorIs(err, errOldSchema)
+	slf.Require().ErrorContains(err, "startup check")
+	slf.False(called)
+}
+
+func (slf *Verify) TestNoChecks() {
+	slf.Require().NoError(trm.New(slf.db, &mockWithTx{}).Verify(slf.ctx))
+}
+
+func TestVerify(t *testing.T) {
+	suite.Run(t, new(Verify))
+}
@@ -0,0 +1,61 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrWriteSetTooLarge = errors.New("trm: transaction write set too large")
+
+// WithMaxRowsAffected bounds the write set of every transaction to n rows, protecting the database
+// from runaway bulk operations that bloat the WAL and hold back vacuum and should have been chunked.
+// RowsAffected of every ExecContext executed through the transaction is accumulated; the statement
+// that takes the total over n fails with ErrWriteSetTooLarge, and so does the commit if the callback
+// ignored that error, so the transaction is rolled back.
+//
+// A non-positive n sets no limit. Results whose RowsAffected fails are not counted, nor are statements executed through
+// PrepareContext or writes through QueryContext, e.g. INSERT ... RETURNING.
+func WithMaxRowsAffected(n int64) Option {
+	return func(c *config) {
+		if n <= 0 {
+			return
+		}
+
+		c.maxRowsAffected = n
+		c.wrappers = append(c.wrappers, func(st *txState, tx Transaction) Transaction {
+			return &writeSetTx{Transaction: tx, st: st, limit: n}
+		})
+	}
+}
+
+type writeSetTx struct {
+	Transaction
+
+	st    *txState
+	limit int64
+}
+
+func (slf *writeSetTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	res, err := slf.Transaction.ExecContext(ctx, query, args...)
+	if err != nil {
+		return res, err
+	}
+
+	n, errRows := res.RowsAffected()
+	if errRows != nil {
+		return res, nil
+	}
+
+	return res, writeSetError(slf.st.writeSet.Add(n), slf.limit)
+}
+
+// writeSetError returns ErrWriteSetTooLarge when total rows exceed limit.
+func writeSetError(total, limit int64) error {
+	if total <= limit {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %d rows affected, limit %d", ErrWriteSetTooLarge, total, limit)
+}
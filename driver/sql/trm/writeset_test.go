@@ -0,0 +1,80 @@
+package trm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+type MaxRowsAffected struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+func (slf *MaxRowsAffected) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &txRepo{}, trm.WithMaxRowsAffected(100))
+}
+
+func (slf *MaxRowsAffected) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *MaxRowsAffected) deleteAll(r *txRepo) error {
+	_, err := r.tx.ExecContext(slf.ctx, "DELETE FROM events")
+	return err
+}
+
+func (slf *MaxRowsAffected) TestWithinLimit() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("DELETE FROM events").WillReturnResult(sqlmock.NewResult(0, 60))
+	slf.mock.ExpectExec("DELETE FROM events").WillReturnResult(sqlmock.NewResult(0, 40))
+	slf.mock.ExpectCommit()
+
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		slf.Require().NoError(slf.deleteAll(r))
+		return slf.deleteAll(r)
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *MaxRowsAffected) TestStatementOverLimit() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("DELETE FROM events").WillReturnResult(sqlmock.NewResult(0, 60))
+	slf.mock.ExpectExec("DELETE FROM events").WillReturnResult(sqlmock.NewResult(0, 41))
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		slf.Require().NoError(slf.deleteAll(r))
+		return slf.deleteAll(r)
+	})
+	slf.Require().ErrorIs(err, trm.ErrWriteSetTooLarge)
+	slf.Require().ErrorContains(err, "101 rows affected, limit 100")
+}
+
+func (slf *MaxRowsAffected) TestIgnoredErrorStillVetoesCommit() {
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectExec("DELETE FROM events").WillReturnResult(sqlmock.NewResult(0, 500))
+	slf.mock.ExpectRollback()
+
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		_ = slf.deleteAll(r)
+		return nil
+	})
+	slf.Require().ErrorIs(err, trm.ErrWriteSetTooLarge)
+}
+
+func TestMaxRowsAffected(t *testing.T) {
+	suite.Run(t, new(MaxRowsAffected))
+}
@@ -0,0 +1,50 @@
+package trm
+
+import (
+	"errors"
+	"reflect"
+)
+
+// sqliteBusy is the primary result code SQLITE_BUSY.
+const sqliteBusy = 5
+
+type coder interface {
+	Code() int
+}
+
+// isBusy reports whether err or any error it wraps is SQLITE_BUSY, extended codes included.
+// The code is read from a Code method, as in modernc.org/sqlite, or from an integer Code field,
+// as in github.com/mattn/go-sqlite3, so no driver is imported.
+func isBusy(err error) bool {
+	for err != nil {
+		if code, ok := errorCode(err); ok {
+			return code&0xff == sqliteBusy
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return false
+}
+
+func errorCode(err error) (int64, bool) {
+	if c, ok := err.(coder); ok { //nolint:errorlint // each error of the chain is inspected by isBusy
+		return int64(c.Code()), true
+	}
+
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return 0, false
+	}
+
+	f := v.FieldByName("Code")
+	if !f.IsValid() || !f.CanInt() {
+		return 0, false
+	}
+
+	return f.Int(), true
+}
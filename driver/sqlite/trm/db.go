@@ -0,0 +1,33 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Query is the set of methods repositories use to execute statements.
+// It is implemented both by the database handle and by Transaction,
+// so a repository stores a single Query field and WithTx replaces it with the transaction.
+type Query interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Transaction is a Query bound to an open transaction.
+type Transaction interface {
+	Query
+	Commit() error
+	Rollback() error
+}
+
+type withTx[T any] interface {
+	WithTx(tx Transaction) T
+}
+
+var (
+	_ Query       = (*sql.DB)(nil)
+	_ Query       = Transaction(nil)
+	_ Transaction = (*connTx)(nil)
+)
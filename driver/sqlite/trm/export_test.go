@@ -0,0 +1,3 @@
+package trm
+
+type Impl[T any] = impl[T]
@@ -0,0 +1,32 @@
+package trm
+
+type Option func(*config)
+
+type config struct {
+	commitAttempts int
+}
+
+func newConfig(opts []Option) *config {
+	cfg := &config{commitAttempts: 1}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// WithCommitRetry retries a COMMIT failing with SQLITE_BUSY up to maxAttempts attempts in total,
+// without running the callback again, waiting 10ms before the first retry and twice as long before
+// each next one, up to 500ms. SQLite keeps the transaction open when COMMIT fails with SQLITE_BUSY
+// (a reader still holds a shared lock), and retrying it is safe: a COMMIT that failed applied nothing.
+// Other errors are returned at once. The code is read from the Code method or field of the error,
+// which the common drivers provide.
+//
+// Only the commit is retried, so this is unrelated to retrying the whole transaction. The other drivers
+// cannot offer it: database/sql ends a *sql.Tx on the first Commit call, whatever its outcome.
+// Waiting stops early when the context of InTx is done; the transaction is then rolled back.
+func WithCommitRetry(maxAttempts int) Option {
+	return func(c *config) {
+		c.commitAttempts = max(maxAttempts, 1)
+	}
+}
@@ -0,0 +1,94 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// The wait before a retry of WithCommitRetry starts at commitBackoff and doubles up to maxCommitBackoff.
+const (
+	commitBackoff    = 10 * time.Millisecond
+	maxCommitBackoff = 500 * time.Millisecond
+)
+
+type impl[T any] struct {
+	db  *sql.DB
+	wt  withTx[T]
+	cfg *config
+}
+
+// New returns a transactor for SQLite that starts every transaction with BEGIN IMMEDIATE.
+//
+// SQLite begins deferred transactions by default: a transaction that reads and then writes
+// has to upgrade its lock, and two such writers fail with "database is locked" however long
+// busy_timeout is. BEGIN IMMEDIATE takes the write lock up front, so concurrent writers wait
+// for each other in busy_timeout instead. Read-only work should use the database handle directly.
+//
+//nolint:revive // exported constructor intentionally returns hidden implementation type
+func New[T withTx[T]](db *sql.DB, wt T, opts ...Option) *impl[T] {
+	return &impl[T]{
+		db:  db,
+		wt:  wt,
+		cfg: newConfig(opts),
+	}
+}
+
+func (slf *impl[T]) InTx(
+	ctx context.Context,
+	fn func(repo T) error,
+) error {
+	conn, err := slf.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_, err = conn.ExecContext(ctx, "BEGIN IMMEDIATE")
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	// COMMIT and ROLLBACK must reach the connection even when ctx is cancelled,
+	// otherwise it would return to the pool with the transaction still open.
+	tx := &connTx{Conn: conn, ctx: context.WithoutCancel(ctx)}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = fn(slf.wt.WithTx(tx))
+	if err != nil {
+		return fmt.Errorf("trm callback: %w", err)
+	}
+
+	err = slf.commit(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+func (slf *impl[T]) commit(ctx context.Context, tx *connTx) error {
+	backoff := commitBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := tx.Commit()
+		if err == nil || attempt >= slf.cfg.commitAttempts || !isBusy(err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		backoff = min(2*backoff, maxCommitBackoff)
+	}
+}
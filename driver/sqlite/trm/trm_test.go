@@ -0,0 +1,178 @@
+package trm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sqlite/trm"
+)
+
+type InTx struct {
+	suite.Suite
+
+	ctx  context.Context
+	mock sqlmock.Sqlmock
+	impl *trm.Impl[*txRepo]
+}
+
+type txRepo struct {
+	tx trm.Transaction
+}
+
+func (r *txRepo) WithTx(tx trm.Transaction) *txRepo {
+	return &txRepo{tx: tx}
+}
+
+func (slf *InTx) SetupTest() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.mock = mock
+	slf.impl = trm.New(db, &txRepo{})
+}
+
+func (slf *InTx) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+}
+
+func (slf *InTx) TestSuccess() {
+	slf.mock.ExpectExec("BEGIN IMMEDIATE").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectExec("UPDATE users SET name = ?").WithArgs("a").WillReturnResult(sqlmock.NewResult(0, 1))
+	slf.mock.ExpectExec("COMMIT").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := slf.impl.InTx(slf.ctx, func(r *txRepo) error {
+		_, err := r.tx.ExecContext(slf.ctx, "UPDATE users SET name = ?", "a")
+		return err
+	})
+	slf.Require().NoError(err)
+}
+
+func (slf *InTx) TestRollbackOnError() {
+	slf.mock.ExpectExec("BEGIN IMMEDIATE").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectExec("ROLLBACK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := slf.impl.InTx(slf.ctx, func(*txRepo) error {
+		return errors.New("err")
+	})
+	slf.Require().EqualError(err, "trm callback: err")
+}
+
+func (slf *InTx) TestBeginError() {
+	slf.mock.ExpectExec("BEGIN IMMEDIATE").WillReturnError(errors.New("database is locked"))
+
+	err := slf.impl.InTx(slf.ctx, func(*txRepo) error {
+		slf.Fail("callback must not run")
+		return nil
+	})
+	slf.Require().EqualError(err, "begin tx: database is locked")
+}
+
+func (slf *InTx) TestCommitErrorRollsBack() {
+	slf.mock.ExpectExec("BEGIN IMMEDIATE").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectExec("COMMIT").WillReturnError(errors.New("busy"))
+	slf.mock.ExpectExec("ROLLBACK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := slf.impl.InTx(slf.ctx, func(*txRepo) error { return nil })
+	slf.Require().EqualError(err, "commit tx: busy")
+}
+
+func (slf *InTx) TestCancelledContextStillRollsBack() {
+	ctx, cancel := context.WithCancel(slf.ctx)
+	slf.mock.ExpectExec("BEGIN IMMEDIATE").WillReturnResult(sqlmock.NewResult(0, 0))
+	slf.mock.ExpectExec("ROLLBACK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := slf.impl.InTx(ctx, func(*txRepo) error {
+		cancel()
+		return ctx.Err()
+	})
+	slf.Require().ErrorIs(err, context.Canceled)
+}
+
+// sqliteError mirrors the error of github.com/mattn/go-sqlite3, whose Code is a field.
+type sqliteError struct {
+	Code         int
+	ExtendedCode int
+}
+
+func (e sqliteError) Error() string {
+	return "database is locked"
+}
+
+// codeError mirrors the error of modernc.org/sqlite, whose Code is a method.
+type codeError struct {
+	code int
+}
+
+func (e *codeError) Error() string {
+	return "database is locked"
+}
+
+func (e *codeError) Code() int {
+	return e.code
+}
+
+func (slf *InTx) TestCommitRetry() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	impl := trm.New(db, &txRepo{}, trm.WithCommitRetry(3))
+
+	mock.ExpectExec("BEGIN IMMEDIATE").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("COMMIT").WillReturnError(sqliteError{Code: 5, ExtendedCode: 5})
+	// SQLITE_BUSY_SNAPSHOT, an extended code of SQLITE_BUSY.
+	mock.ExpectExec("COMMIT").WillReturnError(fmt.Errorf("wrap: %w", &codeError{code: 517}))
+	mock.ExpectExec("COMMIT").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	calls := 0
+	err = impl.InTx(slf.ctx, func(*txRepo) error {
+		calls++
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal(1, calls)
+	slf.Require().NoError(mock.ExpectationsWereMet())
+}
+
+func (slf *InTx) TestCommitRetryExhausted() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	impl := trm.New(db, &txRepo{}, trm.WithCommitRetry(2))
+
+	mock.ExpectExec("BEGIN IMMEDIATE").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("COMMIT").WillReturnError(sqliteError{Code: 5})
+	mock.ExpectExec("COMMIT").WillReturnError(sqliteError{Code: 5})
+	mock.ExpectExec("ROLLBACK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = impl.InTx(slf.ctx, func(*txRepo) error { return nil })
+	slf.Require().EqualError(err, "commit tx: database is locked")
+	slf.Require().NoError(mock.ExpectationsWereMet())
+}
+
+func (slf *InTx) TestCommitRetryOnlyWhenBusy() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	impl := trm.New(db, &txRepo{}, trm.WithCommitRetry(3))
+
+	// SQLITE_CONSTRAINT, e.g. a deferred foreign key violated at commit.
+	errConstraint := sqliteError{Code: 19}
+
+	mock.ExpectExec("BEGIN IMMEDIATE").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("COMMIT").WillReturnError(errConstraint)
+	mock.ExpectExec("ROLLBACK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = impl.InTx(slf.ctx, func(*txRepo) error { return nil })
+	slf.Require().ErrorIs(err, errConstraint)
+	slf.Require().NoError(mock.ExpectationsWereMet())
+}
+
+func TestInTx(t *testing.T) {
+	suite.Run(t, new(InTx))
+}
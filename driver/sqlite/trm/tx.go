@@ -0,0 +1,45 @@
+package trm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var errTxDone = errors.New("transaction has already been committed or rolled back")
+
+// connTx is a transaction opened with an explicit BEGIN IMMEDIATE on a dedicated connection,
+// which database/sql cannot express through TxOptions.
+type connTx struct {
+	*sql.Conn
+
+	ctx  context.Context
+	done bool
+}
+
+// Commit keeps the transaction open when COMMIT fails (e.g. SQLITE_BUSY), so it can still be rolled back.
+func (slf *connTx) Commit() error {
+	if slf.done {
+		return errTxDone
+	}
+
+	_, err := slf.ExecContext(slf.ctx, "COMMIT")
+	if err != nil {
+		return err
+	}
+
+	slf.done = true
+
+	return nil
+}
+
+func (slf *connTx) Rollback() error {
+	if slf.done {
+		return errTxDone
+	}
+
+	slf.done = true
+
+	_, err := slf.ExecContext(slf.ctx, "ROLLBACK")
+	return err
+}
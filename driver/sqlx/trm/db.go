@@ -7,6 +7,9 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// Query is the set of methods repositories use to execute statements.
+// It is implemented both by the database handle and by Transaction,
+// so a repository stores a single Query field and WithTx replaces it with the transaction.
 type Query interface {
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
 	PreparexContext(ctx context.Context, query string) (*sqlx.Stmt, error)
@@ -14,6 +17,7 @@ type Query interface {
 	QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row
 }
 
+// Transaction is a Query bound to an open transaction.
 type Transaction interface {
 	Query
 	Commit() error
@@ -23,3 +27,9 @@ type Transaction interface {
 type withTx[T any] interface {
 	WithTx(tx Transaction) T
 }
+
+var (
+	_ Query       = (*sqlx.DB)(nil)
+	_ Query       = Transaction(nil)
+	_ Transaction = (*sqlx.Tx)(nil)
+)
@@ -44,5 +44,3 @@ func (slf *impl[T]) InTx(
 
 	return nil
 }
-
-var _ Transaction = (*sqlx.Tx)(nil)
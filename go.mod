@@ -9,6 +9,7 @@ require (
 	github.com/pashagolub/pgxmock/v5 v5.0.1
 	github.com/stretchr/testify v1.11.1
 	go.uber.org/mock v0.6.0
+	golang.org/x/time v0.15.0
 )
 
 require (
@@ -0,0 +1,113 @@
+package benchmark_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	pgxtrm "github.com/metalfm/transactor/driver/pgx/trm"
+)
+
+const batchItems = 100
+
+func BenchmarkBatchPostgres(b *testing.B) {
+	items := make([]string, batchItems)
+	for i := range items {
+		items[i] = "item"
+	}
+
+	b.Run("insert=loop", func(b *testing.B) {
+		ctx := context.Background()
+
+		conn, cleanup := preparePgxOrders(ctx, b)
+		defer cleanup()
+
+		tr := pgxtrm.New(conn, &pgxOrderRepo{})
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for b.Loop() {
+			err := tr.InTx(ctx, func(r *pgxOrderRepo) error {
+				return r.CreateOrder(ctx, items)
+			})
+			require.NoError(b, err)
+		}
+	})
+	b.Run("insert=batch", func(b *testing.B) {
+		ctx := context.Background()
+
+		conn, cleanup := preparePgxOrders(ctx, b)
+		defer cleanup()
+
+		tr := pgxtrm.New(conn, &pgxOrderRepo{})
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for b.Loop() {
+			err := tr.InTx(ctx, func(r *pgxOrderRepo) error {
+				return r.CreateOrderBatch(ctx, items)
+			})
+			require.NoError(b, err)
+		}
+	})
+}
+
+type pgxOrderRepo struct {
+	tx pgxtrm.Transaction
+}
+
+func (slf *pgxOrderRepo) WithTx(tx pgxtrm.Transaction) *pgxOrderRepo {
+	return &pgxOrderRepo{tx: tx}
+}
+
+// CreateOrder inserts the items one round trip each, as orderRepo.CreateOrder.
+func (slf *pgxOrderRepo) CreateOrder(ctx context.Context, items []string) error {
+	for _, item := range items {
+		_, err := slf.tx.Exec(ctx, `INSERT INTO orders (item) VALUES ($1)`, item)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateOrderBatch inserts the items in a single round trip.
+func (slf *pgxOrderRepo) CreateOrderBatch(ctx context.Context, items []string) error {
+	batch := &pgx.Batch{}
+	for _, item := range items {
+		batch.Queue(`INSERT INTO orders (item) VALUES ($1)`, item)
+	}
+
+	results := pgxtrm.SendBatch(ctx, slf.tx, batch)
+	for range items {
+		_, err := results.Exec()
+		if err != nil {
+			_ = results.Close()
+			return err
+		}
+	}
+
+	return results.Close()
+}
+
+func preparePgxOrders(ctx context.Context, tb testing.TB) (*pgx.Conn, func()) {
+	conn, err := pgx.Connect(ctx, os.Getenv("DSN_POSTGRES"))
+	require.NoError(tb, err)
+
+	_, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS orders (id SERIAL PRIMARY KEY, item TEXT NOT NULL)`)
+	require.NoError(tb, err)
+
+	return conn, func() {
+		_, err = conn.Exec(ctx, "DROP TABLE orders")
+		require.NoError(tb, err)
+
+		err = conn.Close(ctx)
+		require.NoError(tb, err)
+	}
+}
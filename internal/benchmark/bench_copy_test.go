@@ -0,0 +1,97 @@
+package benchmark_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+const copyItems = 1000
+
+func BenchmarkCopyPostgres(b *testing.B) {
+	items := make([]string, copyItems)
+	for i := range items {
+		items[i] = "item"
+	}
+
+	b.Run("insert=loop", func(b *testing.B) {
+		ctx := context.Background()
+
+		conn, cleanup := prepareOrders(ctx, b)
+		defer cleanup()
+
+		tr := trm.New(conn, &orderRepo{})
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for b.Loop() {
+			err := tr.InTx(ctx, func(r *orderRepo) error {
+				return r.CreateOrder(ctx, items)
+			})
+			require.NoError(b, err)
+		}
+	})
+	b.Run("insert=copy", func(b *testing.B) {
+		ctx := context.Background()
+
+		conn, cleanup := prepareOrders(ctx, b)
+		defer cleanup()
+
+		tr := trm.New(conn, &orderRepo{})
+
+		rows := make([][]any, len(items))
+		for i, item := range items {
+			rows[i] = []any{item}
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for b.Loop() {
+			err := tr.InTx(ctx, func(r *orderRepo) error {
+				_, err := trm.CopyFrom(ctx, r.tx, "orders", []string{"item"}, rows)
+				return err
+			})
+			require.NoError(b, err)
+		}
+	})
+}
+
+type orderRepo struct {
+	tx trm.Transaction
+}
+
+func (slf *orderRepo) WithTx(tx trm.Transaction) *orderRepo {
+	return &orderRepo{tx: tx}
+}
+
+// CreateOrder mirrors svc.RepoOrder.CreateOrder from the example.
+func (slf *orderRepo) CreateOrder(ctx context.Context, items []string) error {
+	for _, item := range items {
+		_, err := slf.tx.ExecContext(ctx, `INSERT INTO orders (item) VALUES ($1)`, item)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func prepareOrders(ctx context.Context, tb testing.TB) (*sql.DB, func()) {
+	conn, cleanup := prepare(ctx, tb)
+
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS orders (id SERIAL PRIMARY KEY, item TEXT NOT NULL)`)
+	require.NoError(tb, err)
+
+	return conn, func() {
+		_, err = conn.ExecContext(ctx, "DROP TABLE orders")
+		require.NoError(tb, err)
+
+		cleanup()
+	}
+}
@@ -0,0 +1,59 @@
+package benchmark_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+const rowCacheReads = 10
+
+func BenchmarkQueryRowCachePostgres(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		opts []trm.Option
+	}{
+		{name: "cache=off"},
+		{name: "cache=on", opts: []trm.Option{trm.WithQueryRowCache()}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			ctx := context.Background()
+
+			conn, cleanup := prepare(ctx, b)
+			defer cleanup()
+
+			var id int
+
+			err := conn.QueryRowContext(ctx, "INSERT INTO users (name) VALUES ($1) RETURNING id", "John Doe").Scan(&id)
+			require.NoError(b, err)
+
+			opts := append([]trm.Option{
+				trm.WithTxOptions(&sql.TxOptions{Isolation: sql.LevelRepeatableRead}),
+			}, bench.opts...)
+			tr := trm.New(conn, &orderRepo{}, opts...)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for b.Loop() {
+				err = tr.InTxCtx(ctx, func(ctx context.Context, r *orderRepo) error {
+					for range rowCacheReads {
+						var name string
+
+						err := trm.QueryRowCached(ctx, r.tx, "SELECT name FROM users WHERE id = $1", id).Scan(&name)
+						if err != nil {
+							return err
+						}
+					}
+
+					return nil
+				})
+				require.NoError(b, err)
+			}
+		})
+	}
+}
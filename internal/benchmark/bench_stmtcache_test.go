@@ -0,0 +1,46 @@
+package benchmark_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+)
+
+const stmtCacheItems = 100
+
+func BenchmarkStmtCachePostgres(b *testing.B) {
+	items := make([]string, stmtCacheItems)
+	for i := range items {
+		items[i] = "item"
+	}
+
+	for _, bench := range []struct {
+		name string
+		opts []trm.Option
+	}{
+		{name: "cache=off"},
+		{name: "cache=on", opts: []trm.Option{trm.WithStmtCache()}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			ctx := context.Background()
+
+			conn, cleanup := prepareOrders(ctx, b)
+			defer cleanup()
+
+			tr := trm.New(conn, &orderRepo{}, bench.opts...)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for b.Loop() {
+				err := tr.InTx(ctx, func(r *orderRepo) error {
+					return r.CreateOrder(ctx, items)
+				})
+				require.NoError(b, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package app_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/internal/example/app"
+	"github.com/metalfm/transactor/internal/example/svc"
+	"github.com/metalfm/transactor/tr"
+	"github.com/metalfm/transactor/trtest"
+)
+
+type ServiceAtomic struct {
+	suite.Suite
+}
+
+// TestCreate fails the last order insert and asserts that neither the user
+// nor the first order persisted. Against a real database, e.g. one started with
+// pgtest.New, the row counts come from the tables themselves.
+func (slf *ServiceAtomic) TestCreate() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	mock.ExpectQuery("SELECT count").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery("SELECT count").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO users").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectRollback()
+	mock.ExpectQuery("SELECT count").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery("SELECT count").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	adapter := svc.NewAdapter(svc.NewRepoUser(db), svc.NewRepoOrder(db))
+	create := func(ctx context.Context, tr tr.Transactor[*svc.Adapter]) error {
+		return app.NewService(tr).Create(ctx, "John Doe", []string{"item1", "item2"})
+	}
+
+	slf.True(trtest.AssertAtomic(slf.T(), db, adapter, create, 2, "users", "orders"))
+	slf.Require().NoError(mock.ExpectationsWereMet())
+}
+
+func TestServiceAtomic(t *testing.T) {
+	suite.Run(t, new(ServiceAtomic))
+}
@@ -0,0 +1,72 @@
+package app_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+	"github.com/metalfm/transactor/internal/example/app"
+	"github.com/metalfm/transactor/internal/example/svc"
+	"github.com/metalfm/transactor/trtest"
+)
+
+// recordingAdapter is a hand-written recording proxy of svc.Adapter:
+// each method the service uses records itself and delegates to the bound adapter.
+type recordingAdapter struct {
+	adapter *svc.Adapter
+	rec     *trtest.Recorder
+}
+
+func (slf *recordingAdapter) CreateUser(ctx context.Context, name string) error {
+	slf.rec.Record("CreateUser", name)
+	return slf.adapter.CreateUser(ctx, name)
+}
+
+func (slf *recordingAdapter) CreateOrder(ctx context.Context, items []string) error {
+	slf.rec.Record("CreateOrder", items)
+	return slf.adapter.CreateOrder(ctx, items)
+}
+
+type ServiceTape struct {
+	suite.Suite
+
+	ctx context.Context
+}
+
+func (slf *ServiceTape) SetupTest() {
+	slf.ctx = context.Background()
+}
+
+func (slf *ServiceTape) TestCreate() {
+	db, mock, err := sqlmock.New()
+	slf.Require().NoError(err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO users").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	adapter := svc.NewAdapter(svc.NewRepoUser(db), svc.NewRepoOrder(db))
+	tape := trtest.Tape(trm.New(db, adapter), func(a *svc.Adapter, rec *trtest.Recorder) *recordingAdapter {
+		return &recordingAdapter{adapter: a, rec: rec}
+	})
+
+	err = app.NewService(tape).Create(slf.ctx, "John Doe", []string{"item1", "item2"})
+	slf.Require().NoError(err)
+	slf.Require().NoError(mock.ExpectationsWereMet())
+
+	slf.Equal([]string{
+		"BEGIN",
+		"CreateUser(John Doe)",
+		"CreateOrder([item1 item2])",
+		"COMMIT",
+	}, tape.Calls())
+}
+
+func TestServiceTape(t *testing.T) {
+	suite.Run(t, new(ServiceTape))
+}
@@ -3,6 +3,7 @@ module github.com/metalfm/transactor/internal/example
 go 1.26
 
 require (
+	github.com/DATA-DOG/go-sqlmock v1.5.2
 	github.com/lib/pq v1.10.9
 	github.com/metalfm/transactor/driver/sql/trm v1.0.1
 	github.com/metalfm/transactor/tr v1.0.1
@@ -17,7 +17,11 @@ func main() {
 	repoUser := svc.NewRepoUser(db)
 	repoOrder := svc.NewRepoOrder(db)
 
-	adapter := svc.NewAdapter(repoUser, repoOrder)
+	adapter, err := svc.NewAdapterChecked(repoUser, repoOrder)
+	if err != nil {
+		panic(err)
+	}
+
 	tr := trm.New(db, adapter)
 
 	ctx := context.Background()
@@ -2,6 +2,7 @@ package svc
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/metalfm/transactor/driver/sql/trm"
 )
@@ -21,6 +22,20 @@ func NewAdapter(
 	}
 }
 
+// NewAdapterChecked is NewAdapter that fails with trm.ErrQueryMismatch unless both
+// repositories were built from the same handle.
+func NewAdapterChecked(
+	repoUser *RepoUser,
+	repoOrder *RepoOrder,
+) (*Adapter, error) {
+	err := trm.SameQuery(repoUser.q, repoOrder.q)
+	if err != nil {
+		return nil, fmt.Errorf("new adapter: %w", err)
+	}
+
+	return NewAdapter(repoUser, repoOrder), nil
+}
+
 func (slf *Adapter) WithTx(tx trm.Transaction) *Adapter {
 	return &Adapter{
 		repoUser:  slf.repoUser.WithTx(tx),
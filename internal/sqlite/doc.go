@@ -0,0 +1,4 @@
+// Package sqlite runs the SQLite driver of the transactor against file-backed databases.
+//
+// It is a separate module, so the cgo driver stays out of the main module.
+package sqlite
@@ -0,0 +1,116 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sqlite/trm"
+)
+
+type counterRepo struct {
+	q trm.Query
+}
+
+func (r *counterRepo) WithTx(tx trm.Transaction) *counterRepo {
+	return &counterRepo{q: tx}
+}
+
+// increment reads the counter before writing it, the pattern deferred transactions deadlock on.
+func (r *counterRepo) increment(ctx context.Context) error {
+	var n int
+
+	err := r.q.QueryRowContext(ctx, "SELECT n FROM counter").Scan(&n)
+	if err != nil {
+		return err
+	}
+
+	// Let the other writers read too.
+	time.Sleep(time.Millisecond)
+
+	_, err = r.q.ExecContext(ctx, "UPDATE counter SET n = ?", n+1)
+
+	return err
+}
+
+type SQLite struct {
+	suite.Suite
+
+	ctx context.Context
+	db  *sql.DB
+}
+
+func (slf *SQLite) SetupTest() {
+	dsn := "file:" + filepath.Join(slf.T().TempDir(), "test.db") + "?_busy_timeout=5000&_journal_mode=WAL"
+
+	db, err := sql.Open("sqlite3", dsn)
+	slf.Require().NoError(err)
+
+	slf.ctx = context.Background()
+	slf.db = db
+
+	_, err = db.ExecContext(slf.ctx, "CREATE TABLE counter (n INTEGER NOT NULL)")
+	slf.Require().NoError(err)
+
+	_, err = db.ExecContext(slf.ctx, "INSERT INTO counter (n) VALUES (0)")
+	slf.Require().NoError(err)
+}
+
+func (slf *SQLite) TearDownTest() {
+	slf.NoError(slf.db.Close())
+}
+
+func (slf *SQLite) counter() int {
+	var n int
+	slf.Require().NoError(slf.db.QueryRowContext(slf.ctx, "SELECT n FROM counter").Scan(&n))
+
+	return n
+}
+
+func (slf *SQLite) TestConcurrentWriters() {
+	const writers = 16
+
+	impl := trm.New(slf.db, &counterRepo{})
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, writers)
+	for range writers {
+		wg.Go(func() {
+			errs <- impl.InTx(slf.ctx, func(r *counterRepo) error {
+				return r.increment(slf.ctx)
+			})
+		})
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		slf.Require().NoError(err)
+	}
+
+	slf.Equal(writers, slf.counter())
+}
+
+func (slf *SQLite) TestRollback() {
+	impl := trm.New(slf.db, &counterRepo{})
+
+	err := impl.InTx(slf.ctx, func(r *counterRepo) error {
+		err := r.increment(slf.ctx)
+		slf.Require().NoError(err)
+
+		return context.Canceled
+	})
+	slf.Require().ErrorIs(err, context.Canceled)
+	slf.Zero(slf.counter())
+}
+
+func TestSQLite(t *testing.T) {
+	suite.Run(t, new(SQLite))
+}
@@ -0,0 +1,30 @@
+package tr
+
+import (
+	"context"
+)
+
+// InTxAcc runs fn in a transaction of base with an accumulator the steps of fn update,
+// and returns its final value once the transaction committed, or the zero value with the error.
+//
+// Every attempt of a retried transaction starts from a copy of initial, so updates made by
+// a rolled back attempt are discarded; the copy is shallow, keep maps and slices out of initial.
+func InTxAcc[T, A any](
+	ctx context.Context,
+	base Transactor[T],
+	initial A,
+	fn func(repo T, acc *A) error,
+) (A, error) {
+	var acc A
+
+	err := base.InTx(ctx, func(repo T) error {
+		acc = initial
+		return fn(repo, &acc)
+	})
+	if err != nil {
+		var zero A
+		return zero, err
+	}
+
+	return acc, nil
+}
@@ -0,0 +1,82 @@
+package tr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/metalfm/transactor/tr"
+	mock_tr "github.com/metalfm/transactor/trtest/mock"
+)
+
+type total struct {
+	items int
+	sum   int
+}
+
+type InTxAcc struct {
+	suite.Suite
+
+	ctx  context.Context
+	base *mock_tr.MockTransactor[*repo]
+}
+
+func (slf *InTxAcc) SetupTest() {
+	slf.ctx = context.Background()
+	slf.base = mock_tr.NewMockTransactor[*repo](gomock.NewController(slf.T()))
+}
+
+func (slf *InTxAcc) TestCommitted() {
+	slf.base.EXPECT().InTx(slf.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*repo) error) error {
+			return fn(&repo{})
+		})
+
+	acc, err := tr.InTxAcc(slf.ctx, slf.base, total{sum: 10}, func(_ *repo, acc *total) error {
+		for _, v := range []int{1, 2, 3} {
+			acc.items++
+			acc.sum += v
+		}
+
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal(total{items: 3, sum: 16}, acc)
+}
+
+func (slf *InTxAcc) TestRetryStartsOver() {
+	slf.base.EXPECT().InTx(slf.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*repo) error) error {
+			_ = fn(&repo{})
+			return fn(&repo{})
+		})
+
+	acc, err := tr.InTxAcc(slf.ctx, slf.base, total{}, func(_ *repo, acc *total) error {
+		acc.items++
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal(total{items: 1}, acc)
+}
+
+func (slf *InTxAcc) TestRolledBack() {
+	expected := errors.New("err")
+	slf.base.EXPECT().InTx(slf.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*repo) error) error {
+			return fn(&repo{})
+		})
+
+	acc, err := tr.InTxAcc(slf.ctx, slf.base, total{sum: 10}, func(_ *repo, acc *total) error {
+		acc.items++
+		return expected
+	})
+	slf.Require().ErrorIs(err, expected)
+	slf.Zero(acc)
+}
+
+func TestInTxAcc(t *testing.T) {
+	suite.Run(t, new(InTxAcc))
+}
@@ -0,0 +1,26 @@
+package tr
+
+import (
+	"context"
+)
+
+type adapted[From, To any] struct {
+	base Transactor[From]
+	conv func(From) To
+}
+
+// Adapt projects the repository of base into another type, e.g. an interface the concrete
+// adapter satisfies, so middleware and test helpers written against Transactor[To] accept it.
+// conv is called with the repository bound to every transaction, the result shares it.
+func Adapt[From, To any](base Transactor[From], conv func(From) To) Transactor[To] {
+	return &adapted[From, To]{
+		base: base,
+		conv: conv,
+	}
+}
+
+func (slf *adapted[From, To]) InTx(ctx context.Context, fn func(To) error) error {
+	return slf.base.InTx(ctx, func(repo From) error {
+		return fn(slf.conv(repo))
+	})
+}
@@ -0,0 +1,63 @@
+package tr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/metalfm/transactor/tr"
+	mock_tr "github.com/metalfm/transactor/trtest/mock"
+)
+
+type named interface {
+	Name() string
+}
+
+func (r *repo) Name() string { return r.name }
+
+type Adapt struct {
+	suite.Suite
+
+	ctx  context.Context
+	base *mock_tr.MockTransactor[*repo]
+	tr   tr.Transactor[named]
+}
+
+func (slf *Adapt) SetupTest() {
+	slf.ctx = context.Background()
+	slf.base = mock_tr.NewMockTransactor[*repo](gomock.NewController(slf.T()))
+	slf.tr = tr.Adapt(slf.base, func(r *repo) named { return r })
+}
+
+func (slf *Adapt) TestProjects() {
+	slf.base.EXPECT().InTx(slf.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*repo) error) error {
+			return fn(&repo{name: "adapter"})
+		})
+
+	var got string
+	err := slf.tr.InTx(slf.ctx, func(r named) error {
+		got = r.Name()
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal("adapter", got)
+}
+
+func (slf *Adapt) TestError() {
+	expected := errors.New("err")
+	slf.base.EXPECT().InTx(slf.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*repo) error) error {
+			return fn(&repo{})
+		})
+
+	err := slf.tr.InTx(slf.ctx, func(named) error { return expected })
+	slf.Require().ErrorIs(err, expected)
+}
+
+func TestAdapt(t *testing.T) {
+	suite.Run(t, new(Adapt))
+}
@@ -0,0 +1,73 @@
+package tr
+
+import (
+	"context"
+	"expvar"
+	"sync"
+)
+
+// expvarMu serializes the lookup and creation of the expvar maps of Expvar.
+var expvarMu sync.Mutex //nolint:gochecknoglobals // the expvar registry is process-wide too
+
+type expvarTransactor[T any] struct {
+	base Transactor[T]
+	vars *expvar.Map
+}
+
+// Expvar counts the transactions run through base and publishes the counters as the expvar
+// map prefix, served at /debug/vars by the expvar package, for services without a metrics stack:
+//
+//   - commits: InTx calls that returned nil;
+//   - rollbacks: InTx calls that failed or panicked;
+//   - in_flight: InTx calls still running.
+//
+// Transactors built with the same prefix share its counters, so building one per test is fine.
+// A prefix already published by other code is left as is and the counters are not published.
+func Expvar[T any](base Transactor[T], prefix string) Transactor[T] {
+	return &expvarTransactor[T]{
+		base: base,
+		vars: expvarMap(prefix),
+	}
+}
+
+func expvarMap(prefix string) *expvar.Map {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+
+	switch v := expvar.Get(prefix).(type) {
+	case *expvar.Map:
+		return v
+	case nil:
+		m := expvar.NewMap(prefix)
+		for _, key := range []string{"commits", "rollbacks", "in_flight"} {
+			m.Add(key, 0)
+		}
+
+		return m
+	default:
+		return new(expvar.Map)
+	}
+}
+
+func (slf *expvarTransactor[T]) InTx(ctx context.Context, fn func(T) error) error {
+	slf.vars.Add("in_flight", 1)
+
+	returned := false
+	defer func() {
+		slf.vars.Add("in_flight", -1)
+		if !returned {
+			slf.vars.Add("rollbacks", 1)
+		}
+	}()
+
+	err := slf.base.InTx(ctx, fn)
+	returned = true
+
+	if err != nil {
+		slf.vars.Add("rollbacks", 1)
+	} else {
+		slf.vars.Add("commits", 1)
+	}
+
+	return err
+}
@@ -0,0 +1,86 @@
+package tr_test
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/metalfm/transactor/tr"
+	mock_tr "github.com/metalfm/transactor/trtest/mock"
+)
+
+type Expvar struct {
+	suite.Suite
+
+	ctx    context.Context
+	base   *mock_tr.MockTransactor[*repo]
+	prefix string
+	tr     tr.Transactor[*repo]
+}
+
+func (slf *Expvar) SetupTest() {
+	slf.ctx = context.Background()
+	slf.base = mock_tr.NewMockTransactor[*repo](gomock.NewController(slf.T()))
+	// expvar names are process-wide: keep them unique across tests and -count runs.
+	slf.prefix = fmt.Sprintf("test_tx_%s_%p", slf.T().Name(), slf)
+	slf.tr = tr.Expvar[*repo](slf.base, slf.prefix)
+}
+
+func (slf *Expvar) vars() string {
+	v := expvar.Get(slf.prefix)
+	slf.Require().NotNil(v)
+
+	return v.String()
+}
+
+func (slf *Expvar) TestCounts() {
+	slf.JSONEq(`{"commits": 0, "rollbacks": 0, "in_flight": 0}`, slf.vars())
+
+	slf.base.EXPECT().InTx(slf.ctx, gomock.Any()).
+		DoAndReturn(func(context.Context, func(*repo) error) error {
+			slf.JSONEq(`{"commits": 0, "rollbacks": 0, "in_flight": 1}`, slf.vars())
+			return nil
+		})
+	slf.base.EXPECT().InTx(slf.ctx, gomock.Any()).Return(errors.New("err"))
+
+	slf.Require().NoError(slf.tr.InTx(slf.ctx, func(*repo) error { return nil }))
+	slf.Require().Error(slf.tr.InTx(slf.ctx, func(*repo) error { return nil }))
+	slf.JSONEq(`{"commits": 1, "rollbacks": 1, "in_flight": 0}`, slf.vars())
+}
+
+func (slf *Expvar) TestPanicCountsAsRollback() {
+	slf.base.EXPECT().InTx(slf.ctx, gomock.Any()).
+		DoAndReturn(func(context.Context, func(*repo) error) error { panic("boom") })
+
+	slf.Panics(func() {
+		_ = slf.tr.InTx(slf.ctx, func(*repo) error { return nil })
+	})
+	slf.JSONEq(`{"commits": 0, "rollbacks": 1, "in_flight": 0}`, slf.vars())
+}
+
+func (slf *Expvar) TestSamePrefixSharesCounters() {
+	other := tr.Expvar[*repo](slf.base, slf.prefix)
+	slf.base.EXPECT().InTx(slf.ctx, gomock.Any()).Return(nil).Times(2)
+
+	slf.Require().NoError(slf.tr.InTx(slf.ctx, func(*repo) error { return nil }))
+	slf.Require().NoError(other.InTx(slf.ctx, func(*repo) error { return nil }))
+	slf.JSONEq(`{"commits": 2, "rollbacks": 0, "in_flight": 0}`, slf.vars())
+}
+
+func (slf *Expvar) TestPrefixTakenByOtherVar() {
+	prefix := fmt.Sprintf("test_tx_taken_%p", slf)
+	expvar.NewString(prefix).Set("taken")
+
+	slf.base.EXPECT().InTx(slf.ctx, gomock.Any()).Return(nil)
+	slf.Require().NoError(tr.Expvar[*repo](slf.base, prefix).InTx(slf.ctx, func(*repo) error { return nil }))
+	slf.Equal(`"taken"`, expvar.Get(prefix).String())
+}
+
+func TestExpvar(t *testing.T) {
+	suite.Run(t, new(Expvar))
+}
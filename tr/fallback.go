@@ -0,0 +1,45 @@
+package tr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type fallback[T any] struct {
+	primary        Transactor[T]
+	secondary      Transactor[T]
+	shouldFallback func(error) bool
+}
+
+// Fallback runs InTx on primary and retries it on secondary when primary failed
+// before the callback was invoked (e.g. begin failure) and shouldFallback accepts the error.
+//
+// Once the callback has been started on primary, its error is returned as is:
+// the callback is never executed twice, even if primary rolled back.
+func Fallback[T any](primary, secondary Transactor[T], shouldFallback func(error) bool) Transactor[T] {
+	return &fallback[T]{
+		primary:        primary,
+		secondary:      secondary,
+		shouldFallback: shouldFallback,
+	}
+}
+
+func (slf *fallback[T]) InTx(ctx context.Context, fn func(T) error) error {
+	called := false
+
+	err := slf.primary.InTx(ctx, func(repo T) error {
+		called = true
+		return fn(repo)
+	})
+	if err == nil || called || !slf.shouldFallback(err) {
+		return err
+	}
+
+	errSecondary := slf.secondary.InTx(ctx, fn)
+	if errSecondary != nil {
+		return fmt.Errorf("fallback: %w", errors.Join(err, errSecondary))
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package tr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/metalfm/transactor/tr"
+	mock_tr "github.com/metalfm/transactor/trtest/mock"
+)
+
+var errBegin = errors.New("begin")
+
+type repo struct {
+	name string
+}
+
+type Fallback struct {
+	suite.Suite
+
+	ctx       context.Context
+	ctrl      *gomock.Controller
+	primary   *mock_tr.MockTransactor[*repo]
+	secondary *mock_tr.MockTransactor[*repo]
+	tr        tr.Transactor[*repo]
+}
+
+func (slf *Fallback) SetupTest() {
+	slf.ctx = context.Background()
+	slf.ctrl = gomock.NewController(slf.T())
+	slf.primary = mock_tr.NewMockTransactor[*repo](slf.ctrl)
+	slf.secondary = mock_tr.NewMockTransactor[*repo](slf.ctrl)
+	slf.tr = tr.Fallback(slf.primary, slf.secondary, func(err error) bool {
+		return errors.Is(err, errBegin)
+	})
+}
+
+func (slf *Fallback) TestPrimarySuccess() {
+	slf.primary.EXPECT().InTx(slf.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*repo) error) error {
+			return fn(&repo{name: "primary"})
+		})
+
+	var got string
+	err := slf.tr.InTx(slf.ctx, func(r *repo) error {
+		got = r.name
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal("primary", got)
+}
+
+func (slf *Fallback) TestBeginFailureFallsBack() {
+	slf.primary.EXPECT().InTx(slf.ctx, gomock.Any()).Return(errBegin)
+	slf.secondary.EXPECT().InTx(slf.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*repo) error) error {
+			return fn(&repo{name: "secondary"})
+		})
+
+	var got string
+	err := slf.tr.InTx(slf.ctx, func(r *repo) error {
+		got = r.name
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal("secondary", got)
+}
+
+func (slf *Fallback) TestUnclassifiedErrorNoFallback() {
+	expected := errors.New("config")
+	slf.primary.EXPECT().InTx(slf.ctx, gomock.Any()).Return(expected)
+
+	err := slf.tr.InTx(slf.ctx, func(*repo) error { return nil })
+	slf.Require().ErrorIs(err, expected)
+}
+
+func (slf *Fallback) TestCallbackStartedNoFallback() {
+	slf.primary.EXPECT().InTx(slf.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*repo) error) error {
+			_ = fn(&repo{name: "primary"})
+			return errBegin
+		})
+
+	calls := 0
+	err := slf.tr.InTx(slf.ctx, func(*repo) error {
+		calls++
+		return nil
+	})
+	slf.Require().ErrorIs(err, errBegin)
+	slf.Equal(1, calls)
+}
+
+func (slf *Fallback) TestBothFail() {
+	expected := errors.New("secondary")
+	slf.primary.EXPECT().InTx(slf.ctx, gomock.Any()).Return(errBegin)
+	slf.secondary.EXPECT().InTx(slf.ctx, gomock.Any()).Return(expected)
+
+	err := slf.tr.InTx(slf.ctx, func(*repo) error { return nil })
+	slf.Require().ErrorIs(err, errBegin)
+	slf.Require().ErrorIs(err, expected)
+}
+
+func TestFallback(t *testing.T) {
+	suite.Run(t, new(Fallback))
+}
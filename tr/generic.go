@@ -0,0 +1,62 @@
+package tr
+
+import (
+	"context"
+	"fmt"
+)
+
+type generic[T, Tx any] struct {
+	begin    func(ctx context.Context) (Tx, error)
+	commit   func(tx Tx) error
+	rollback func(tx Tx) error
+	bind     func(tx Tx) T
+}
+
+// NewGeneric adapts any store with transactions, e.g. a message broker or an in-memory store,
+// from three functions and bind, which builds the repository bound to a transaction.
+//
+// InTx begins a transaction, calls fn with the bound repository and commits when fn returns nil.
+// Otherwise, and when fn panics or commit fails, the transaction is rolled back and the panic
+// propagates; rollback errors are ignored, so rollback must also tolerate a failed commit.
+// Errors are wrapped like the drivers' ones: begin tx, trm callback and commit tx.
+func NewGeneric[T, Tx any](
+	begin func(ctx context.Context) (Tx, error),
+	commit func(tx Tx) error,
+	rollback func(tx Tx) error,
+	bind func(tx Tx) T,
+) Transactor[T] {
+	return &generic[T, Tx]{
+		begin:    begin,
+		commit:   commit,
+		rollback: rollback,
+		bind:     bind,
+	}
+}
+
+func (slf *generic[T, Tx]) InTx(ctx context.Context, fn func(T) error) error {
+	tx, err := slf.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = slf.rollback(tx)
+		}
+	}()
+
+	err = fn(slf.bind(tx))
+	if err != nil {
+		return fmt.Errorf("trm callback: %w", err)
+	}
+
+	err = slf.commit(tx)
+	if err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	committed = true
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package tr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/tr"
+)
+
+// memTx buffers writes to a map until commit.
+type memTx struct {
+	store   map[string]string
+	pending map[string]string
+}
+
+type memRepo struct {
+	tx *memTx
+}
+
+func (r *memRepo) Put(k, v string) {
+	r.tx.pending[k] = v
+}
+
+type Generic struct {
+	suite.Suite
+
+	ctx       context.Context
+	store     map[string]string
+	log       []string
+	beginErr  error
+	commitErr error
+	tr        tr.Transactor[*memRepo]
+}
+
+func (slf *Generic) SetupTest() {
+	slf.ctx = context.Background()
+	slf.store = map[string]string{}
+	slf.log = nil
+	slf.beginErr = nil
+	slf.commitErr = nil
+	slf.tr = tr.NewGeneric(
+		func(context.Context) (*memTx, error) {
+			slf.log = append(slf.log, "begin")
+			return &memTx{store: slf.store, pending: map[string]string{}}, slf.beginErr
+		},
+		func(tx *memTx) error {
+			slf.log = append(slf.log, "commit")
+			if slf.commitErr != nil {
+				return slf.commitErr
+			}
+
+			for k, v := range tx.pending {
+				tx.store[k] = v
+			}
+
+			return nil
+		},
+		func(*memTx) error {
+			slf.log = append(slf.log, "rollback")
+			return nil
+		},
+		func(tx *memTx) *memRepo { return &memRepo{tx: tx} },
+	)
+}
+
+func (slf *Generic) TestCommit() {
+	err := slf.tr.InTx(slf.ctx, func(r *memRepo) error {
+		r.Put("a", "1")
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal(map[string]string{"a": "1"}, slf.store)
+	slf.Equal([]string{"begin", "commit"}, slf.log)
+}
+
+func (slf *Generic) TestCallbackError() {
+	err := slf.tr.InTx(slf.ctx, func(r *memRepo) error {
+		r.Put("a", "1")
+		return errors.New("err")
+	})
+	slf.Require().EqualError(err, "trm callback: err")
+	slf.Empty(slf.store)
+	slf.Equal([]string{"begin", "rollback"}, slf.log)
+}
+
+func (slf *Generic) TestBeginError() {
+	slf.beginErr = errors.New("err")
+
+	err := slf.tr.InTx(slf.ctx, func(*memRepo) error { return nil })
+	slf.Require().EqualError(err, "begin tx: err")
+	slf.Equal([]string{"begin"}, slf.log)
+}
+
+func (slf *Generic) TestCommitError() {
+	slf.commitErr = errors.New("err")
+
+	err := slf.tr.InTx(slf.ctx, func(*memRepo) error { return nil })
+	slf.Require().EqualError(err, "commit tx: err")
+	slf.Equal([]string{"begin", "commit", "rollback"}, slf.log)
+}
+
+func (slf *Generic) TestPanic() {
+	slf.PanicsWithValue("boom", func() {
+		_ = slf.tr.InTx(slf.ctx, func(*memRepo) error { panic("boom") })
+	})
+	slf.Equal([]string{"begin", "rollback"}, slf.log)
+}
+
+func TestGeneric(t *testing.T) {
+	suite.Run(t, new(Generic))
+}
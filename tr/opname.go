@@ -0,0 +1,41 @@
+package tr
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrMissingOpName = errors.New("tr: transaction has no operation name")
+
+type opNameKey struct{}
+
+type requireOpName[T any] struct {
+	base Transactor[T]
+}
+
+// WithOpName returns a copy of ctx carrying the operation name of the transactions started with it,
+// e.g. "CreateOrder", for metrics and tracing labels.
+func WithOpName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, opNameKey{}, name)
+}
+
+// OpName returns the operation name carried by ctx, or an empty string.
+func OpName(ctx context.Context) string {
+	name, _ := ctx.Value(opNameKey{}).(string)
+	return name
+}
+
+// RequireOpName fails InTx with ErrMissingOpName when ctx carries no operation name,
+// before base is called. It is meant to be strict: wrap the transactor with it only
+// in development and test configurations to nudge every call site toward a name.
+func RequireOpName[T any](base Transactor[T]) Transactor[T] {
+	return &requireOpName[T]{base: base}
+}
+
+func (slf *requireOpName[T]) InTx(ctx context.Context, fn func(T) error) error {
+	if OpName(ctx) == "" {
+		return ErrMissingOpName
+	}
+
+	return slf.base.InTx(ctx, fn)
+}
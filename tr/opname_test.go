@@ -0,0 +1,47 @@
+package tr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/metalfm/transactor/tr"
+	mock_tr "github.com/metalfm/transactor/trtest/mock"
+)
+
+type RequireOpName struct {
+	suite.Suite
+
+	ctx  context.Context
+	base *mock_tr.MockTransactor[*repo]
+	tr   tr.Transactor[*repo]
+}
+
+func (slf *RequireOpName) SetupTest() {
+	slf.ctx = context.Background()
+	slf.base = mock_tr.NewMockTransactor[*repo](gomock.NewController(slf.T()))
+	slf.tr = tr.RequireOpName(slf.base)
+}
+
+func (slf *RequireOpName) TestMissing() {
+	err := slf.tr.InTx(slf.ctx, func(*repo) error { return nil })
+	slf.Require().ErrorIs(err, tr.ErrMissingOpName)
+}
+
+func (slf *RequireOpName) TestNamed() {
+	ctx := tr.WithOpName(slf.ctx, "CreateOrder")
+	slf.base.EXPECT().InTx(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(*repo) error) error {
+			slf.Equal("CreateOrder", tr.OpName(ctx))
+			return fn(&repo{})
+		})
+
+	err := slf.tr.InTx(ctx, func(*repo) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func TestRequireOpName(t *testing.T) {
+	suite.Run(t, new(RequireOpName))
+}
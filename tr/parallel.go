@@ -0,0 +1,44 @@
+package tr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// InTxParallel runs fn for every item in its own transaction on base, with at most maxConc
+// transactions at a time (at least one). Items are atomic individually, not together:
+// a failed item does not roll back the others and does not stop the remaining items.
+//
+// It waits for every item and returns the errors joined, each wrapped with the item index.
+func InTxParallel[T, E any](
+	ctx context.Context,
+	base Transactor[T],
+	items []E,
+	maxConc int,
+	fn func(repo T, item E) error,
+) error {
+	sem := make(chan struct{}, max(maxConc, 1))
+	errs := make([]error, len(items))
+
+	var wg sync.WaitGroup
+	for i, item := range items {
+		sem <- struct{}{}
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+
+			err := base.InTx(ctx, func(repo T) error {
+				return fn(repo, item)
+			})
+			if err != nil {
+				errs[i] = fmt.Errorf("item %d: %w", i, err)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,103 @@
+package tr_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/metalfm/transactor/tr"
+	mock_tr "github.com/metalfm/transactor/trtest/mock"
+)
+
+type InTxParallel struct {
+	suite.Suite
+
+	ctx  context.Context
+	base *mock_tr.MockTransactor[*repo]
+}
+
+func (slf *InTxParallel) SetupTest() {
+	slf.ctx = context.Background()
+	slf.base = mock_tr.NewMockTransactor[*repo](gomock.NewController(slf.T()))
+}
+
+func (slf *InTxParallel) TestJoinsErrors() {
+	errOdd := errors.New("odd")
+	slf.base.EXPECT().InTx(slf.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*repo) error) error {
+			return fn(&repo{})
+		}).
+		Times(4)
+
+	var (
+		mu   sync.Mutex
+		seen []int
+	)
+	err := tr.InTxParallel(slf.ctx, slf.base, []int{1, 2, 3, 4}, 2, func(_ *repo, item int) error {
+		mu.Lock()
+		seen = append(seen, item)
+		mu.Unlock()
+
+		if item%2 == 1 {
+			return errOdd
+		}
+
+		return nil
+	})
+	slf.Require().ErrorIs(err, errOdd)
+	slf.Contains(err.Error(), "item 0: odd")
+	slf.Contains(err.Error(), "item 2: odd")
+	slf.ElementsMatch([]int{1, 2, 3, 4}, seen)
+}
+
+func (slf *InTxParallel) TestBoundsConcurrency() {
+	synctest.Test(slf.T(), func(t *testing.T) {
+		release := make(chan struct{})
+
+		var running, peak atomic.Int32
+		base := mock_tr.NewMockTransactor[*repo](gomock.NewController(t))
+		base.EXPECT().InTx(slf.ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, fn func(*repo) error) error {
+				n := running.Add(1)
+				if n > peak.Load() {
+					peak.Store(n)
+				}
+
+				<-release
+				running.Add(-1)
+
+				return fn(&repo{})
+			}).
+			Times(5)
+
+		done := make(chan error)
+		go func() {
+			done <- tr.InTxParallel(slf.ctx, base, []int{1, 2, 3, 4, 5}, 2, func(*repo, int) error {
+				return nil
+			})
+		}()
+
+		synctest.Wait()
+		require.Equal(t, int32(2), running.Load())
+
+		close(release)
+		require.NoError(t, <-done)
+		require.Equal(t, int32(2), peak.Load())
+	})
+}
+
+func (slf *InTxParallel) TestEmpty() {
+	err := tr.InTxParallel(slf.ctx, slf.base, []int(nil), 0, func(*repo, int) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func TestInTxParallel(t *testing.T) {
+	suite.Run(t, new(InTxParallel))
+}
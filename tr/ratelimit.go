@@ -0,0 +1,49 @@
+package tr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+type rateLimited[T any] struct {
+	base    Transactor[T]
+	limiter *rate.Limiter
+	observe func(ctx context.Context, wait time.Duration)
+}
+
+// RateLimited caps the rate of transactions started through base with a token bucket of limit
+// transactions per second and the given burst. InTx waits for a token before calling base and
+// returns the error of the wait when ctx is done first, e.g. when it cannot be satisfied before
+// the deadline.
+//
+// observe, if not nil, receives how long every call waited, so a metric shows when limiting kicks in.
+func RateLimited[T any](
+	base Transactor[T],
+	limit rate.Limit,
+	burst int,
+	observe func(ctx context.Context, wait time.Duration),
+) Transactor[T] {
+	return &rateLimited[T]{
+		base:    base,
+		limiter: rate.NewLimiter(limit, burst),
+		observe: observe,
+	}
+}
+
+func (slf *rateLimited[T]) InTx(ctx context.Context, fn func(T) error) error {
+	start := time.Now()
+
+	err := slf.limiter.Wait(ctx)
+	if slf.observe != nil {
+		slf.observe(ctx, time.Since(start))
+	}
+
+	if err != nil {
+		return fmt.Errorf("rate limit: %w", err)
+	}
+
+	return slf.base.InTx(ctx, fn)
+}
@@ -0,0 +1,62 @@
+package tr_test
+
+import (
+	"context"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/time/rate"
+
+	"github.com/metalfm/transactor/tr"
+	mock_tr "github.com/metalfm/transactor/trtest/mock"
+)
+
+type RateLimited struct {
+	suite.Suite
+
+	base  *mock_tr.MockTransactor[*repo]
+	waits []time.Duration
+	tr    tr.Transactor[*repo]
+}
+
+// setup builds the transactor with the t of the synctest bubble, as FailNow must run on its goroutine.
+func (slf *RateLimited) setup(t *testing.T) {
+	slf.base = mock_tr.NewMockTransactor[*repo](gomock.NewController(t))
+	slf.waits = nil
+	slf.tr = tr.RateLimited(slf.base, rate.Every(time.Second), 1, func(_ context.Context, wait time.Duration) {
+		slf.waits = append(slf.waits, wait)
+	})
+}
+
+func (slf *RateLimited) TestWaitsForToken() {
+	synctest.Test(slf.T(), func(t *testing.T) {
+		slf.setup(t)
+		ctx := context.Background()
+		slf.base.EXPECT().InTx(ctx, gomock.Any()).Return(nil).Times(2)
+
+		require.NoError(t, slf.tr.InTx(ctx, func(*repo) error { return nil }))
+		require.NoError(t, slf.tr.InTx(ctx, func(*repo) error { return nil }))
+		require.Equal(t, []time.Duration{0, time.Second}, slf.waits)
+	})
+}
+
+func (slf *RateLimited) TestContextDeadline() {
+	synctest.Test(slf.T(), func(t *testing.T) {
+		slf.setup(t)
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		slf.base.EXPECT().InTx(ctx, gomock.Any()).Return(nil)
+
+		require.NoError(t, slf.tr.InTx(ctx, func(*repo) error { return nil }))
+		require.ErrorContains(t, slf.tr.InTx(ctx, func(*repo) error { return nil }), "rate limit")
+	})
+}
+
+func TestRateLimited(t *testing.T) {
+	suite.Run(t, new(RateLimited))
+}
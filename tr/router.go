@@ -0,0 +1,41 @@
+package tr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+)
+
+var ErrNoRoute = errors.New("tr: no transactor for route")
+
+type router[T any] struct {
+	key    func(ctx context.Context) string
+	routes map[string]Transactor[T]
+}
+
+// Router dispatches every InTx call to the transactor of routes registered under key(ctx),
+// e.g. an SQL transactor for "orders" and a search index transactor for "documents", possibly
+// of different drivers, brought to the same repository type with Adapt. Calls whose key has no
+// route fail with ErrNoRoute without running the callback. routes is copied.
+//
+// Each call runs on exactly one store: Router gives several stores a uniform InTx surface, not
+// atomicity across them. An operation that must change two stores together still needs an outbox
+// or a saga, as two InTx calls routed to different stores commit independently.
+func Router[T any](key func(ctx context.Context) string, routes map[string]Transactor[T]) Transactor[T] {
+	return &router[T]{
+		key:    key,
+		routes: maps.Clone(routes),
+	}
+}
+
+func (slf *router[T]) InTx(ctx context.Context, fn func(T) error) error {
+	k := slf.key(ctx)
+
+	base, ok := slf.routes[k]
+	if !ok {
+		return fmt.Errorf("route %q: %w", k, ErrNoRoute)
+	}
+
+	return base.InTx(ctx, fn)
+}
@@ -0,0 +1,69 @@
+package tr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/metalfm/transactor/tr"
+	mock_tr "github.com/metalfm/transactor/trtest/mock"
+)
+
+type storeKey struct{}
+
+type Router struct {
+	suite.Suite
+
+	ctx    context.Context
+	sql    *mock_tr.MockTransactor[*repo]
+	search *mock_tr.MockTransactor[*repo]
+	tr     tr.Transactor[*repo]
+}
+
+func (slf *Router) SetupTest() {
+	ctrl := gomock.NewController(slf.T())
+
+	slf.ctx = context.Background()
+	slf.sql = mock_tr.NewMockTransactor[*repo](ctrl)
+	slf.search = mock_tr.NewMockTransactor[*repo](ctrl)
+	slf.tr = tr.Router(func(ctx context.Context) string {
+		store, _ := ctx.Value(storeKey{}).(string)
+		return store
+	}, map[string]tr.Transactor[*repo]{
+		"sql":    slf.sql,
+		"search": slf.search,
+	})
+}
+
+func (slf *Router) TestDispatchesByKey() {
+	ctx := context.WithValue(slf.ctx, storeKey{}, "search")
+	slf.search.EXPECT().InTx(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*repo) error) error {
+			return fn(&repo{name: "search"})
+		})
+
+	var got string
+	err := slf.tr.InTx(ctx, func(r *repo) error {
+		got = r.name
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal("search", got)
+}
+
+func (slf *Router) TestNoRoute() {
+	ctx := context.WithValue(slf.ctx, storeKey{}, "cache")
+
+	err := slf.tr.InTx(ctx, func(*repo) error {
+		slf.Fail("callback must not run")
+		return nil
+	})
+	slf.Require().ErrorIs(err, tr.ErrNoRoute)
+	slf.Require().EqualError(err, `route "cache": tr: no transactor for route`)
+}
+
+func TestRouter(t *testing.T) {
+	suite.Run(t, new(Router))
+}
@@ -0,0 +1,67 @@
+package tr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrShadowPanicked = errors.New("tr: shadow transaction panicked")
+
+type shadow[T any] struct {
+	primary       Transactor[T]
+	shadow        Transactor[T]
+	onShadowError func(error)
+}
+
+// Shadow runs InTx on primary, which stays authoritative, and after primary commits
+// runs the same callback on shadow, e.g. a database being migrated to.
+// Shadow errors never fail the call: they are passed to onShadowError, panics of the
+// shadow too, as ErrShadowPanicked. A nil onShadowError ignores them.
+//
+// The shadow runs synchronously, so every call takes as long as both transactions. It keeps
+// the deadline of ctx but not its cancellation: a caller going away right after primary
+// committed does not abort the mirror.
+//
+// The callback runs once per transactor, so it must not have side effects outside
+// the repository. Nothing is mirrored when primary fails.
+func Shadow[T any](primary, shadowTr Transactor[T], onShadowError func(error)) Transactor[T] {
+	return &shadow[T]{
+		primary:       primary,
+		shadow:        shadowTr,
+		onShadowError: onShadowError,
+	}
+}
+
+func (slf *shadow[T]) InTx(ctx context.Context, fn func(T) error) error {
+	err := slf.primary.InTx(ctx, fn)
+	if err != nil {
+		return err
+	}
+
+	err = slf.mirror(ctx, fn)
+	if err != nil && slf.onShadowError != nil {
+		slf.onShadowError(err)
+	}
+
+	return nil
+}
+
+// mirror runs fn on the shadow, turning a panic into an error.
+func (slf *shadow[T]) mirror(ctx context.Context, fn func(T) error) (err error) {
+	shadowCtx := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+
+		shadowCtx, cancel = context.WithDeadline(shadowCtx, deadline)
+		defer cancel()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrShadowPanicked, r)
+		}
+	}()
+
+	return slf.shadow.InTx(shadowCtx, fn)
+}
@@ -0,0 +1,128 @@
+package tr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/metalfm/transactor/tr"
+	mock_tr "github.com/metalfm/transactor/trtest/mock"
+)
+
+type Shadow struct {
+	suite.Suite
+
+	ctx       context.Context
+	primary   *mock_tr.MockTransactor[*repo]
+	shadow    *mock_tr.MockTransactor[*repo]
+	shadowErr []error
+	tr        tr.Transactor[*repo]
+}
+
+func (slf *Shadow) SetupTest() {
+	ctrl := gomock.NewController(slf.T())
+
+	slf.ctx = context.Background()
+	slf.primary = mock_tr.NewMockTransactor[*repo](ctrl)
+	slf.shadow = mock_tr.NewMockTransactor[*repo](ctrl)
+	slf.shadowErr = nil
+	slf.tr = tr.Shadow(slf.primary, slf.shadow, func(err error) {
+		slf.shadowErr = append(slf.shadowErr, err)
+	})
+}
+
+func (slf *Shadow) expect(m *mock_tr.MockTransactor[*repo], name string) {
+	m.EXPECT().InTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*repo) error) error {
+			return fn(&repo{name: name})
+		})
+}
+
+func (slf *Shadow) TestMirrors() {
+	slf.expect(slf.primary, "primary")
+	slf.expect(slf.shadow, "shadow")
+
+	var got []string
+	err := slf.tr.InTx(slf.ctx, func(r *repo) error {
+		got = append(got, r.name)
+		return nil
+	})
+	slf.Require().NoError(err)
+	slf.Equal([]string{"primary", "shadow"}, got)
+	slf.Empty(slf.shadowErr)
+}
+
+func (slf *Shadow) TestShadowErrorReported() {
+	expected := errors.New("shadow")
+	slf.expect(slf.primary, "primary")
+	slf.shadow.EXPECT().InTx(gomock.Any(), gomock.Any()).Return(expected)
+
+	err := slf.tr.InTx(slf.ctx, func(*repo) error { return nil })
+	slf.Require().NoError(err)
+	slf.Equal([]error{expected}, slf.shadowErr)
+}
+
+func (slf *Shadow) TestShadowPanicReported() {
+	slf.expect(slf.primary, "primary")
+	slf.shadow.EXPECT().InTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, func(*repo) error) error {
+			panic("boom")
+		})
+
+	err := slf.tr.InTx(slf.ctx, func(*repo) error { return nil })
+	slf.Require().NoError(err)
+	slf.Require().Len(slf.shadowErr, 1)
+	slf.Require().ErrorIs(slf.shadowErr[0], tr.ErrShadowPanicked)
+	slf.Contains(slf.shadowErr[0].Error(), "boom")
+}
+
+func (slf *Shadow) TestShadowContext() {
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(slf.ctx, deadline)
+
+	slf.primary.EXPECT().InTx(ctx, gomock.Any()).
+		DoAndReturn(func(context.Context, func(*repo) error) error {
+			cancel() // e.g. the client went away right after the commit
+			return nil
+		})
+	slf.shadow.EXPECT().InTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ func(*repo) error) error {
+			slf.NoError(ctx.Err())
+
+			shadowDeadline, ok := ctx.Deadline()
+			slf.True(ok)
+			slf.Equal(deadline, shadowDeadline)
+
+			return nil
+		})
+
+	err := slf.tr.InTx(ctx, func(*repo) error { return nil })
+	slf.Require().NoError(err)
+	slf.Empty(slf.shadowErr)
+}
+
+func (slf *Shadow) TestNilOnShadowError() {
+	slf.tr = tr.Shadow(slf.primary, slf.shadow, nil)
+	slf.expect(slf.primary, "primary")
+	slf.shadow.EXPECT().InTx(gomock.Any(), gomock.Any()).Return(errors.New("shadow"))
+
+	err := slf.tr.InTx(slf.ctx, func(*repo) error { return nil })
+	slf.Require().NoError(err)
+}
+
+func (slf *Shadow) TestPrimaryErrorNotMirrored() {
+	expected := errors.New("primary")
+	slf.primary.EXPECT().InTx(slf.ctx, gomock.Any()).Return(expected)
+
+	err := slf.tr.InTx(slf.ctx, func(*repo) error { return nil })
+	slf.Require().ErrorIs(err, expected)
+	slf.Empty(slf.shadowErr)
+}
+
+func TestShadow(t *testing.T) {
+	suite.Run(t, new(Shadow))
+}
@@ -0,0 +1,83 @@
+package tr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrFlightPanicked = errors.New("tr: shared transaction panicked")
+
+type flight struct {
+	done chan struct{}
+	err  error
+}
+
+type singleFlight[T any] struct {
+	base  Transactor[T]
+	keyFn func(ctx context.Context) string
+
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// SingleFlight collapses concurrent InTx calls with the same key into one transaction:
+// only the first caller's callback runs and every caller receives its error (or nil).
+// Callbacks must therefore be idempotent and interchangeable for a given key.
+// An empty key disables deduplication for the call.
+//
+// Waiting callers stop waiting when their context is done; the shared transaction
+// keeps running with the first caller's context. When it panics, the first caller panics
+// and the waiting ones receive ErrFlightPanicked.
+func SingleFlight[T any](base Transactor[T], keyFn func(ctx context.Context) string) Transactor[T] {
+	return &singleFlight[T]{
+		base:    base,
+		keyFn:   keyFn,
+		flights: map[string]*flight{},
+	}
+}
+
+func (slf *singleFlight[T]) InTx(ctx context.Context, fn func(T) error) error {
+	key := slf.keyFn(ctx)
+	if key == "" {
+		return slf.base.InTx(ctx, fn)
+	}
+
+	slf.mu.Lock()
+	if f, ok := slf.flights[key]; ok {
+		slf.mu.Unlock()
+
+		select {
+		case <-f.done:
+			return f.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	f := &flight{done: make(chan struct{})}
+	slf.flights[key] = f
+	slf.mu.Unlock()
+
+	defer func() {
+		r := recover()
+		if r != nil {
+			f.err = fmt.Errorf("%w: %v", ErrFlightPanicked, r)
+		}
+
+		slf.mu.Lock()
+		delete(slf.flights, key)
+		slf.mu.Unlock()
+
+		close(f.done)
+
+		if r != nil {
+			panic(r)
+		}
+	}()
+
+	f.err = slf.base.InTx(ctx, fn)
+
+	return f.err
+}
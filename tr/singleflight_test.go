@@ -0,0 +1,141 @@
+package tr_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"testing/synctest"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/metalfm/transactor/tr"
+	mock_tr "github.com/metalfm/transactor/trtest/mock"
+)
+
+type keyCtx struct{}
+
+type SingleFlight struct {
+	suite.Suite
+
+	ctrl *gomock.Controller
+	base *mock_tr.MockTransactor[*repo]
+	tr   tr.Transactor[*repo]
+}
+
+func (slf *SingleFlight) SetupTest() {
+	slf.setup(slf.T())
+}
+
+// setup builds the transactor; tests in a synctest bubble call it again with the t of the bubble.
+func (slf *SingleFlight) setup(t *testing.T) {
+	slf.ctrl = gomock.NewController(t)
+	slf.base = mock_tr.NewMockTransactor[*repo](slf.ctrl)
+	slf.tr = tr.SingleFlight(slf.base, func(ctx context.Context) string {
+		key, _ := ctx.Value(keyCtx{}).(string)
+		return key
+	})
+}
+
+func (slf *SingleFlight) run(ctx context.Context, n int, release chan struct{}) []error {
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Go(func() {
+			errs[i] = slf.tr.InTx(ctx, func(*repo) error { return nil })
+		})
+	}
+
+	synctest.Wait()
+	close(release)
+	wg.Wait()
+
+	return errs
+}
+
+func (slf *SingleFlight) TestSharedResult() {
+	synctest.Test(slf.T(), func(t *testing.T) {
+		slf.setup(t)
+
+		expected := errors.New("rollback")
+		release := make(chan struct{})
+
+		slf.base.EXPECT().InTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, func(*repo) error) error {
+				<-release
+				return expected
+			}).
+			Times(1)
+
+		errs := slf.run(context.WithValue(context.Background(), keyCtx{}, "k"), 3, release)
+		for _, err := range errs {
+			require.ErrorIs(t, err, expected)
+		}
+	})
+}
+
+func (slf *SingleFlight) TestPanicNotShared() {
+	synctest.Test(slf.T(), func(t *testing.T) {
+		slf.setup(t)
+
+		release := make(chan struct{})
+
+		slf.base.EXPECT().InTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, func(*repo) error) error {
+				<-release
+				panic("boom")
+			}).
+			Times(1)
+
+		ctx := context.WithValue(context.Background(), keyCtx{}, "k")
+		recovered := make(chan any, 1)
+
+		go func() {
+			defer func() { recovered <- recover() }()
+
+			_ = slf.tr.InTx(ctx, func(*repo) error { return nil })
+		}()
+		synctest.Wait()
+
+		errWaiter := make(chan error, 1)
+		go func() { errWaiter <- slf.tr.InTx(ctx, func(*repo) error { return nil }) }()
+		synctest.Wait()
+		close(release)
+
+		require.Equal(t, "boom", <-recovered)
+		require.ErrorIs(t, <-errWaiter, tr.ErrFlightPanicked)
+	})
+}
+
+func (slf *SingleFlight) TestEmptyKeyNotShared() {
+	synctest.Test(slf.T(), func(t *testing.T) {
+		slf.setup(t)
+
+		release := make(chan struct{})
+
+		slf.base.EXPECT().InTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, func(*repo) error) error {
+				<-release
+				return nil
+			}).
+			Times(2)
+
+		errs := slf.run(context.Background(), 2, release)
+		require.Equal(t, []error{nil, nil}, errs)
+	})
+}
+
+func (slf *SingleFlight) TestSequentialCallsNotShared() {
+	slf.base.EXPECT().InTx(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	ctx := context.WithValue(context.Background(), keyCtx{}, "k")
+	slf.Require().NoError(slf.tr.InTx(ctx, func(*repo) error { return nil }))
+	slf.Require().NoError(slf.tr.InTx(ctx, func(*repo) error { return nil }))
+}
+
+func TestSingleFlight(t *testing.T) {
+	suite.Run(t, new(SingleFlight))
+}
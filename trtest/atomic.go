@@ -0,0 +1,95 @@
+package trtest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+	"github.com/metalfm/transactor/tr"
+)
+
+var ErrInjected = errors.New("trtest: injected failure")
+
+// AssertAtomic proves that operation is atomic: it runs operation with a transactor over db and
+// adapter whose transactions fail every statement after the first failAfter ones with ErrInjected,
+// and reports a failure through tb unless operation returned an error and every table holds as
+// many rows as before. It returns whether the assertion held.
+//
+// Statements are counted across all transactions of operation; PrepareContext fails with
+// trm.ErrPrepareTransformed, as prepared statements cannot be counted. Pass the number of
+// statements of operation minus one to fail its last step. The tables are counted with
+// SELECT count(*) on db outside of any transaction.
+func AssertAtomic[T interface{ WithTx(tx trm.Transaction) T }](
+	tb testing.TB,
+	db *sql.DB,
+	adapter T,
+	operation func(ctx context.Context, tr tr.Transactor[T]) error,
+	failAfter int,
+	tables ...string,
+) bool {
+	tb.Helper()
+
+	ctx := context.Background()
+
+	before, ok := countTables(ctx, tb, db, tables)
+	if !ok {
+		return false
+	}
+
+	var statements atomic.Int64
+
+	transactor := trm.New(db, adapter, trm.WithArgTransformer(func(_ context.Context, _ string, args []any) ([]any, error) {
+		if statements.Add(1) > int64(failAfter) {
+			return nil, ErrInjected
+		}
+
+		return args, nil
+	}))
+
+	err := operation(ctx, transactor)
+	if statements.Load() <= int64(failAfter) {
+		tb.Errorf("trtest: operation ran %d statements, no failure injected after %d", statements.Load(), failAfter)
+		return false
+	}
+
+	if err == nil {
+		tb.Errorf("trtest: operation succeeded although statement %d failed", failAfter+1)
+		return false
+	}
+
+	after, ok := countTables(ctx, tb, db, tables)
+	if !ok {
+		return false
+	}
+
+	held := true
+
+	for i, table := range tables {
+		if after[i] != before[i] {
+			tb.Errorf("trtest: %s holds %d rows after the failed operation, %d before", table, after[i], before[i])
+			held = false
+		}
+	}
+
+	return held
+}
+
+func countTables(ctx context.Context, tb testing.TB, db *sql.DB, tables []string) ([]int64, bool) {
+	tb.Helper()
+
+	counts := make([]int64, len(tables))
+
+	for i, table := range tables {
+		//nolint:gosec // table names are written by the test, not taken from users
+		err := db.QueryRowContext(ctx, "SELECT count(*) FROM "+table).Scan(&counts[i])
+		if err != nil {
+			tb.Errorf("trtest: count rows of %s: %v", table, err)
+			return nil, false
+		}
+	}
+
+	return counts, true
+}
@@ -0,0 +1,138 @@
+package trtest_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+	"github.com/metalfm/transactor/tr"
+	"github.com/metalfm/transactor/trtest"
+)
+
+type ledger struct {
+	q trm.Query
+}
+
+func (slf *ledger) WithTx(tx trm.Transaction) *ledger {
+	return &ledger{q: tx}
+}
+
+func (slf *ledger) post(ctx context.Context, item string) error {
+	_, err := slf.q.ExecContext(ctx, "INSERT INTO entries (item) VALUES ($1)", item)
+	return err
+}
+
+type AssertAtomic struct {
+	suite.Suite
+
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	rec  *recorder
+}
+
+func (slf *AssertAtomic) SetupTest() {
+	var err error
+
+	slf.db, slf.mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	slf.Require().NoError(err)
+
+	slf.rec = &recorder{TB: slf.T()}
+}
+
+func (slf *AssertAtomic) TearDownTest() {
+	slf.NoError(slf.mock.ExpectationsWereMet())
+	_ = slf.db.Close()
+}
+
+func (slf *AssertAtomic) expectCount(n int) {
+	slf.mock.ExpectQuery("SELECT count(*) FROM entries").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(n))
+}
+
+func (slf *AssertAtomic) expectPost(item string) {
+	slf.mock.ExpectExec("INSERT INTO entries (item) VALUES ($1)").WithArgs(item).WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func postBoth(ctx context.Context, r *ledger) error {
+	err := r.post(ctx, "debit")
+	if err != nil {
+		return err
+	}
+
+	return r.post(ctx, "credit")
+}
+
+func (slf *AssertAtomic) TestAtomic() {
+	slf.expectCount(0)
+	slf.mock.ExpectBegin()
+	slf.expectPost("debit")
+	slf.mock.ExpectRollback()
+	slf.expectCount(0)
+
+	ok := trtest.AssertAtomic(slf.rec, slf.db, &ledger{q: slf.db},
+		func(ctx context.Context, tr tr.Transactor[*ledger]) error {
+			return tr.InTx(ctx, func(r *ledger) error { return postBoth(ctx, r) })
+		}, 1, "entries")
+	slf.True(ok)
+	slf.Empty(slf.rec.errs)
+}
+
+func (slf *AssertAtomic) TestPartialWrite() {
+	slf.expectCount(0)
+	slf.mock.ExpectBegin()
+	slf.expectPost("debit")
+	slf.mock.ExpectCommit()
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectRollback()
+	slf.expectCount(1)
+
+	ok := trtest.AssertAtomic(slf.rec, slf.db, &ledger{q: slf.db},
+		func(ctx context.Context, tr tr.Transactor[*ledger]) error {
+			err := tr.InTx(ctx, func(r *ledger) error { return r.post(ctx, "debit") })
+			if err != nil {
+				return err
+			}
+
+			return tr.InTx(ctx, func(r *ledger) error { return r.post(ctx, "credit") })
+		}, 1, "entries")
+	slf.False(ok)
+	slf.Equal([]string{"trtest: entries holds 1 rows after the failed operation, 0 before"}, slf.rec.errs)
+}
+
+func (slf *AssertAtomic) TestSwallowedError() {
+	slf.expectCount(0)
+	slf.mock.ExpectBegin()
+	slf.mock.ExpectCommit()
+
+	ok := trtest.AssertAtomic(slf.rec, slf.db, &ledger{q: slf.db},
+		func(ctx context.Context, tr tr.Transactor[*ledger]) error {
+			return tr.InTx(ctx, func(r *ledger) error {
+				_ = r.post(ctx, "debit")
+				return nil
+			})
+		}, 0, "entries")
+	slf.False(ok)
+	slf.Equal([]string{"trtest: operation succeeded although statement 1 failed"}, slf.rec.errs)
+}
+
+func (slf *AssertAtomic) TestNoFailureInjected() {
+	slf.expectCount(0)
+	slf.mock.ExpectBegin()
+	slf.expectPost("debit")
+	slf.expectPost("credit")
+	slf.mock.ExpectCommit()
+
+	ok := trtest.AssertAtomic(slf.rec, slf.db, &ledger{q: slf.db},
+		func(ctx context.Context, tr tr.Transactor[*ledger]) error {
+			return tr.InTx(ctx, func(r *ledger) error { return postBoth(ctx, r) })
+		}, 2, "entries")
+	slf.False(ok)
+	slf.Equal([]string{"trtest: operation ran 2 statements, no failure injected after 2"}, slf.rec.errs)
+}
+
+func TestAssertAtomic(t *testing.T) {
+	suite.Run(t, new(AssertAtomic))
+}
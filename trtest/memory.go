@@ -0,0 +1,408 @@
+package trtest
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/metalfm/transactor/driver/sql/trm"
+	"github.com/metalfm/transactor/tr"
+)
+
+var ErrUnsupportedQuery = errors.New("trtest: query not supported by the memory database")
+
+// MemoryTransactor is a transactor over an in-memory database, for unit tests of business logic
+// without a database server or sqlmock expectations: rows inserted by a committed transaction
+// persist, those of a rolled back one disappear, and other transactions only see committed rows.
+//
+// The database understands a small SQL subset:
+//
+//	INSERT INTO table (col, ...) VALUES ($1, ...), ...
+//	SELECT col, ... FROM table [WHERE col = $1 AND ...]
+//	SELECT count(*) FROM table [WHERE col = $1 AND ...]
+//
+// where values are placeholders ($n or ?) or NULL, and columns are named: SELECT * is not
+// supported. Every other statement, UPDATE and DELETE included, fails with ErrUnsupportedQuery
+// rather than returning a made-up result. Tables need no schema: they are created by their
+// first insert.
+type MemoryTransactor[T interface{ WithTx(tx trm.Transaction) T }] struct {
+	transactor tr.Transactor[T]
+	db         *sql.DB
+	store      *memStore
+}
+
+// NewMemoryTransactor returns a MemoryTransactor over a new empty database, whose adapter is
+// built by adapter from the database. The transactions are *sql.Tx, so repositories written
+// against trm.Query run unchanged as long as their statements stay within the supported subset,
+// and opts apply as with trm.New.
+func NewMemoryTransactor[T interface{ WithTx(tx trm.Transaction) T }](
+	adapter func(db *sql.DB) T,
+	opts ...trm.Option,
+) *MemoryTransactor[T] {
+	store := &memStore{tables: map[string][]memRow{}}
+	db := sql.OpenDB(store)
+
+	return &MemoryTransactor[T]{
+		transactor: trm.New(db, adapter(db), opts...),
+		db:         db,
+		store:      store,
+	}
+}
+
+// InTx runs fn in a transaction of the in-memory database, see tr.Transactor.
+func (slf *MemoryTransactor[T]) InTx(ctx context.Context, fn func(T) error) error {
+	return slf.transactor.InTx(ctx, fn)
+}
+
+// DB returns the in-memory database, e.g. to build repositories used outside of transactions or
+// to pass it to AssertAtomic. Statements made on it outside of a transaction commit at once.
+func (slf *MemoryTransactor[T]) DB() *sql.DB {
+	return slf.db
+}
+
+// Rows returns a copy of the committed rows of table, in insertion order, keyed by column.
+// Values are those stored by database/sql, e.g. int64 for any integer.
+func (slf *MemoryTransactor[T]) Rows(table string) []map[string]any {
+	slf.store.mu.Lock()
+	defer slf.store.mu.Unlock()
+
+	rows := make([]map[string]any, 0, len(slf.store.tables[table]))
+	for _, row := range slf.store.tables[table] {
+		rows = append(rows, maps.Clone(row))
+	}
+
+	return rows
+}
+
+type memRow = map[string]any
+
+// memStore holds the committed rows; it is the driver.Connector of the database.
+type memStore struct {
+	mu     sync.Mutex
+	tables map[string][]memRow
+}
+
+func (slf *memStore) Connect(context.Context) (driver.Conn, error) {
+	return &memConn{store: slf}, nil
+}
+
+func (slf *memStore) Driver() driver.Driver {
+	return memDriver{store: slf}
+}
+
+type memDriver struct {
+	store *memStore
+}
+
+func (slf memDriver) Open(string) (driver.Conn, error) {
+	return slf.store.Connect(context.Background())
+}
+
+// memConn runs statements on the store; inserts of its open transaction are kept in pending
+// until the commit.
+type memConn struct {
+	store   *memStore
+	pending map[string][]memRow
+	inTx    bool
+}
+
+func (slf *memConn) Prepare(query string) (driver.Stmt, error) {
+	return &memStmt{conn: slf, query: query}, nil
+}
+
+func (slf *memConn) Close() error {
+	return nil
+}
+
+func (slf *memConn) Begin() (driver.Tx, error) {
+	return slf.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (slf *memConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	slf.inTx = true
+	slf.pending = map[string][]memRow{}
+
+	return memTx{conn: slf}, nil
+}
+
+func (slf *memConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	table, rows, err := parseInsert(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if slf.inTx {
+		slf.pending[table] = append(slf.pending[table], rows...)
+	} else {
+		slf.store.mu.Lock()
+		slf.store.tables[table] = append(slf.store.tables[table], rows...)
+		slf.store.mu.Unlock()
+	}
+
+	return driver.RowsAffected(len(rows)), nil
+}
+
+func (slf *memConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	m := selectRe.FindStringSubmatch(query)
+	if m == nil || slices.Contains(splitList(m[1]), "*") {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedQuery, query)
+	}
+
+	columns, table := splitList(m[1]), m[2]
+
+	filter, err := parseWhere(m[3], args)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, query)
+	}
+
+	slf.store.mu.Lock()
+	rows := append(append([]memRow(nil), slf.store.tables[table]...), slf.pending[table]...)
+	slf.store.mu.Unlock()
+
+	rows = slices.DeleteFunc(rows, func(row memRow) bool { return !filter.matches(row) })
+
+	if len(columns) == 1 && strings.EqualFold(columns[0], "count(*)") {
+		return &memRows{columns: columns, values: [][]driver.Value{{int64(len(rows))}}}, nil
+	}
+
+	values := make([][]driver.Value, len(rows))
+	for i, row := range rows {
+		values[i] = make([]driver.Value, len(columns))
+		for j, col := range columns {
+			values[i][j] = row[col]
+		}
+	}
+
+	return &memRows{columns: columns, values: values}, nil
+}
+
+type memTx struct {
+	conn *memConn
+}
+
+func (slf memTx) Commit() error {
+	store := slf.conn.store
+
+	store.mu.Lock()
+	for table, rows := range slf.conn.pending {
+		store.tables[table] = append(store.tables[table], rows...)
+	}
+	store.mu.Unlock()
+
+	return slf.Rollback()
+}
+
+func (slf memTx) Rollback() error {
+	slf.conn.inTx = false
+	slf.conn.pending = nil
+
+	return nil
+}
+
+type memStmt struct {
+	conn  *memConn
+	query string
+}
+
+func (slf *memStmt) Close() error {
+	return nil
+}
+
+func (slf *memStmt) NumInput() int {
+	return -1
+}
+
+func (slf *memStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return slf.conn.ExecContext(context.Background(), slf.query, named(args))
+}
+
+func (slf *memStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return slf.conn.QueryContext(context.Background(), slf.query, named(args))
+}
+
+type memRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (slf *memRows) Columns() []string {
+	return slf.columns
+}
+
+func (slf *memRows) Close() error {
+	return nil
+}
+
+func (slf *memRows) Next(dest []driver.Value) error {
+	if len(slf.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, slf.values[0])
+	slf.values = slf.values[1:]
+
+	return nil
+}
+
+var (
+	insertRe = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s+(\w+)\s*\(([^)]*)\)\s*VALUES\s*(.+?)\s*;?\s*$`)
+	tupleRe  = regexp.MustCompile(`\(([^)]*)\)`)
+	selectRe = regexp.MustCompile(`(?is)^\s*SELECT\s+(.+?)\s+FROM\s+(\w+)(?:\s+WHERE\s+(.+?))?\s*;?\s*$`)
+	andRe    = regexp.MustCompile(`(?i)\s+AND\s+`)
+	equalRe  = regexp.MustCompile(`^(\w+)\s*=\s*(\S+)$`)
+)
+
+// parseInsert returns the table and rows inserted by query.
+func parseInsert(query string, args []driver.NamedValue) (string, []memRow, error) {
+	m := insertRe.FindStringSubmatch(query)
+	if m == nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrUnsupportedQuery, query)
+	}
+
+	columns := splitList(m[2])
+	next := 0
+
+	var rows []memRow
+
+	for _, tuple := range tupleRe.FindAllStringSubmatch(m[3], -1) {
+		values := splitList(tuple[1])
+		if len(values) != len(columns) {
+			return "", nil, fmt.Errorf(
+				"%w: %d values for %d columns: %s", ErrUnsupportedQuery, len(values), len(columns), query,
+			)
+		}
+
+		row := make(memRow, len(columns))
+
+		for i, value := range values {
+			v, err := resolveValue(value, args, &next)
+			if err != nil {
+				return "", nil, fmt.Errorf("%w: %s", err, query)
+			}
+
+			row[columns[i]] = v
+		}
+
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return "", nil, fmt.Errorf("%w: %s", ErrUnsupportedQuery, query)
+	}
+
+	return m[1], rows, nil
+}
+
+// memFilter holds the value every column of a WHERE clause must equal.
+type memFilter map[string]any
+
+// parseWhere returns the filter of the WHERE clause where, empty when there is none.
+func parseWhere(where string, args []driver.NamedValue) (memFilter, error) {
+	filter := memFilter{}
+	if where == "" {
+		return filter, nil
+	}
+
+	next := 0
+
+	for _, cond := range andRe.Split(where, -1) {
+		m := equalRe.FindStringSubmatch(strings.TrimSpace(cond))
+		if m == nil {
+			return nil, fmt.Errorf("%w: condition %s", ErrUnsupportedQuery, cond)
+		}
+
+		v, err := resolveValue(m[2], args, &next)
+		if err != nil {
+			return nil, err
+		}
+
+		filter[m[1]] = v
+	}
+
+	return filter, nil
+}
+
+// matches reports whether row has the values of the filter; as in SQL, NULL equals nothing.
+func (slf memFilter) matches(row memRow) bool {
+	for col, want := range slf {
+		got := row[col]
+		if got == nil || want == nil {
+			return false
+		}
+
+		gotBytes, gotOK := got.([]byte)
+		wantBytes, wantOK := want.([]byte)
+
+		switch {
+		case gotOK || wantOK:
+			if !gotOK || !wantOK || !bytes.Equal(gotBytes, wantBytes) {
+				return false
+			}
+		case got != want:
+			return false
+		}
+	}
+
+	return true
+}
+
+// resolveValue returns the value of a placeholder or NULL; next is the index of the next ?.
+func resolveValue(value string, args []driver.NamedValue, next *int) (any, error) {
+	i := -1
+
+	switch {
+	case strings.EqualFold(value, "NULL"):
+		return nil, nil //nolint:nilnil // NULL is a nil value, not an error
+	case value == "?":
+		i = *next
+		*next++
+	case strings.HasPrefix(value, "$"):
+		n, err := strconv.Atoi(value[1:])
+		if err == nil {
+			i = n - 1
+		}
+	}
+
+	if i < 0 {
+		return nil, fmt.Errorf("%w: value %s", ErrUnsupportedQuery, value)
+	}
+
+	if i >= len(args) {
+		return nil, fmt.Errorf("%w: missing argument %s", ErrUnsupportedQuery, value)
+	}
+
+	if b, ok := args[i].Value.([]byte); ok {
+		// The caller may reuse its buffer once the statement returned.
+		return bytes.Clone(b), nil
+	}
+
+	return args[i].Value, nil
+}
+
+func splitList(list string) []string {
+	items := strings.Split(list, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+
+	return items
+}
+
+func named(args []driver.Value) []driver.NamedValue {
+	nv := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		nv[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+
+	return nv
+}
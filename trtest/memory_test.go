@@ -0,0 +1,159 @@
+package trtest_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/tr"
+	"github.com/metalfm/transactor/trtest"
+)
+
+var errFailed = errors.New("failed")
+
+type MemoryTransactor struct {
+	suite.Suite
+
+	ctx context.Context
+	mem *trtest.MemoryTransactor[*ledger]
+}
+
+func (slf *MemoryTransactor) SetupTest() {
+	slf.ctx = context.Background()
+	slf.mem = trtest.NewMemoryTransactor(func(db *sql.DB) *ledger { return &ledger{q: db} })
+}
+
+func (slf *MemoryTransactor) TestCommitPersists() {
+	err := slf.mem.InTx(slf.ctx, func(r *ledger) error { return postBoth(slf.ctx, r) })
+	slf.Require().NoError(err)
+
+	slf.Equal([]map[string]any{{"item": "debit"}, {"item": "credit"}}, slf.mem.Rows("entries"))
+}
+
+func (slf *MemoryTransactor) TestRollbackDiscards() {
+	err := slf.mem.InTx(slf.ctx, func(r *ledger) error {
+		err := postBoth(slf.ctx, r)
+		if err != nil {
+			return err
+		}
+
+		return errFailed
+	})
+	slf.Require().ErrorIs(err, errFailed)
+
+	slf.Empty(slf.mem.Rows("entries"))
+}
+
+func (slf *MemoryTransactor) TestIsolation() {
+	err := slf.mem.InTx(slf.ctx, func(r *ledger) error {
+		slf.Require().NoError(r.post(slf.ctx, "debit"))
+
+		var own, others int
+		slf.Require().NoError(r.q.QueryRowContext(slf.ctx, "SELECT count(*) FROM entries").Scan(&own))
+		slf.Require().NoError(slf.mem.DB().QueryRowContext(slf.ctx, "SELECT count(*) FROM entries").Scan(&others))
+		slf.Equal(1, own)
+		slf.Equal(0, others)
+
+		return nil
+	})
+	slf.Require().NoError(err)
+
+	rows, err := slf.mem.DB().QueryContext(slf.ctx, "SELECT item FROM entries")
+	slf.Require().NoError(err)
+
+	defer func() { _ = rows.Close() }()
+
+	var items []string
+	for rows.Next() {
+		var item string
+		slf.Require().NoError(rows.Scan(&item))
+		items = append(items, item)
+	}
+	slf.Require().NoError(rows.Err())
+	slf.Equal([]string{"debit"}, items)
+}
+
+func (slf *MemoryTransactor) TestInsertValues() {
+	res, err := slf.mem.DB().ExecContext(slf.ctx,
+		"INSERT INTO users (id, name, email) VALUES ($2, $1, NULL), ($3, $4, $5)", "ann", 1, 2, "bob", "bob@example.com")
+	slf.Require().NoError(err)
+
+	n, err := res.RowsAffected()
+	slf.Require().NoError(err)
+	slf.Equal(int64(2), n)
+
+	slf.Equal([]map[string]any{
+		{"id": int64(1), "name": "ann", "email": nil},
+		{"id": int64(2), "name": "bob", "email": "bob@example.com"},
+	}, slf.mem.Rows("users"))
+}
+
+func (slf *MemoryTransactor) TestBytesCopied() {
+	payload := []byte("debit")
+
+	_, err := slf.mem.DB().ExecContext(slf.ctx, "INSERT INTO entries (payload) VALUES ($1)", payload)
+	slf.Require().NoError(err)
+
+	payload[0] = 'X'
+	slf.Equal([]map[string]any{{"payload": []byte("debit")}}, slf.mem.Rows("entries"))
+}
+
+func (slf *MemoryTransactor) TestWhere() {
+	_, err := slf.mem.DB().ExecContext(slf.ctx,
+		"INSERT INTO users (id, name, team) VALUES ($1, $2, $3), ($4, $5, $3), ($6, $7, NULL)",
+		1, "ann", "ops", 2, "bob", 3, "eve")
+	slf.Require().NoError(err)
+
+	var name string
+	slf.Require().NoError(slf.mem.DB().QueryRowContext(slf.ctx,
+		"SELECT name FROM users WHERE team = $1 AND id = $2", "ops", 2).Scan(&name))
+	slf.Equal("bob", name)
+
+	var n int
+	slf.Require().NoError(slf.mem.DB().QueryRowContext(slf.ctx,
+		"SELECT count(*) FROM users WHERE team = ?", "ops").Scan(&n))
+	slf.Equal(2, n)
+
+	err = slf.mem.DB().QueryRowContext(slf.ctx, "SELECT name FROM users WHERE team = NULL").Scan(&name)
+	slf.Require().ErrorIs(err, sql.ErrNoRows)
+}
+
+func (slf *MemoryTransactor) TestUnsupportedQuery() {
+	_, err := slf.mem.DB().ExecContext(slf.ctx, "INSERT INTO entries (item) VALUES ($1)", "debit")
+	slf.Require().NoError(err)
+
+	for _, query := range []string{
+		"SELECT * FROM entries",
+		"SELECT item, * FROM entries",
+		"SELECT item FROM entries WHERE item <> $1",
+		"SELECT item FROM entries WHERE item = $1 OR item = $2",
+	} {
+		_, err := slf.mem.DB().QueryContext(slf.ctx, query, "debit", "credit")
+		slf.Require().ErrorIs(err, trtest.ErrUnsupportedQuery, query)
+	}
+
+	for _, query := range []string{"UPDATE entries SET item = $1", "DELETE FROM entries"} {
+		_, err := slf.mem.DB().ExecContext(slf.ctx, query, "credit")
+		slf.Require().ErrorIs(err, trtest.ErrUnsupportedQuery, query)
+	}
+
+	slf.Equal([]map[string]any{{"item": "debit"}}, slf.mem.Rows("entries"))
+}
+
+func (slf *MemoryTransactor) TestAssertAtomic() {
+	rec := &recorder{TB: slf.T()}
+
+	ok := trtest.AssertAtomic(rec, slf.mem.DB(), &ledger{q: slf.mem.DB()},
+		func(ctx context.Context, tr tr.Transactor[*ledger]) error {
+			return tr.InTx(ctx, func(r *ledger) error { return postBoth(ctx, r) })
+		}, 1, "entries")
+	slf.True(ok)
+	slf.Empty(rec.errs)
+}
+
+func TestMemoryTransactor(t *testing.T) {
+	suite.Run(t, new(MemoryTransactor))
+}
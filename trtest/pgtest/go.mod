@@ -0,0 +1,10 @@
+module github.com/metalfm/transactor/trtest/pgtest
+
+go 1.26
+
+require (
+	github.com/lib/pq v1.10.9
+	github.com/stretchr/testify v1.11.1
+	github.com/testcontainers/testcontainers-go v0.40.0
+	github.com/testcontainers/testcontainers-go/modules/postgres v0.40.0
+)
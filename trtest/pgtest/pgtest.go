@@ -0,0 +1,80 @@
+// Package pgtest starts a throwaway PostgreSQL in Docker for integration tests and benchmarks,
+// so they run with a plain go test instead of a manually provisioned DSN.
+//
+// It is a separate module, so the container dependencies stay out of the main module.
+package pgtest
+
+import (
+	"database/sql"
+	"testing"
+
+	// Registers the "postgres" driver used by New.
+	_ "github.com/lib/pq"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+const defaultImage = "postgres:16.8-alpine3.20"
+
+type Option func(*config)
+
+type config struct {
+	image string
+}
+
+// WithImage replaces the PostgreSQL image, postgres:16.8-alpine3.20 by default.
+func WithImage(image string) Option {
+	return func(c *config) {
+		c.image = image
+	}
+}
+
+// New starts a PostgreSQL container, applies migrations (several statements separated by
+// semicolons, may be empty) and returns a database connected to it. The container and the
+// database are removed when tb finishes; any failure fails tb. Docker must be available.
+func New(tb testing.TB, migrations string, opts ...Option) *sql.DB {
+	tb.Helper()
+
+	cfg := &config{image: defaultImage}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	ctx := tb.Context()
+
+	ctr, err := postgres.Run(ctx, cfg.image,
+		postgres.WithDatabase("test"),
+		postgres.WithUsername("test"),
+		postgres.WithPassword("test"),
+		postgres.BasicWaitStrategies(),
+	)
+	testcontainers.CleanupContainer(tb, ctr)
+	if err != nil {
+		tb.Fatalf("pgtest: start container: %v", err)
+	}
+
+	dsn, err := ctr.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		tb.Fatalf("pgtest: connection string: %v", err)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		tb.Fatalf("pgtest: open: %v", err)
+	}
+
+	tb.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	if migrations == "" {
+		return db
+	}
+
+	_, err = db.ExecContext(ctx, migrations)
+	if err != nil {
+		tb.Fatalf("pgtest: migrate: %v", err)
+	}
+
+	return db
+}
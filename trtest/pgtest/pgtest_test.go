@@ -0,0 +1,23 @@
+package pgtest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/metalfm/transactor/trtest/pgtest"
+)
+
+func TestNew(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	db := pgtest.New(t, `
+		CREATE TABLE orders (id BIGINT PRIMARY KEY);
+		INSERT INTO orders VALUES (1), (2);
+	`)
+
+	var n int
+	require.NoError(t, db.QueryRowContext(t.Context(), "SELECT count(*) FROM orders").Scan(&n))
+	require.Equal(t, 2, n)
+}
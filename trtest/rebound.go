@@ -0,0 +1,115 @@
+package trtest
+
+import (
+	"reflect"
+	"testing"
+)
+
+// AssertAllRebound checks that every field reachable from adapter that can hold tx
+// (an interface implemented by tx or a field of the same type as tx) holds exactly tx.
+//
+// Call it with the value returned by WithTx to catch repositories that were
+// left bound to the original database connection.
+func AssertAllRebound(tb testing.TB, adapter, tx any) bool {
+	tb.Helper()
+
+	txType := reflect.TypeOf(tx)
+	if txType == nil {
+		tb.Errorf("trtest: transaction is nil")
+		return false
+	}
+
+	adapterValue := reflect.ValueOf(adapter)
+	if !adapterValue.IsValid() || adapterValue.Kind() == reflect.Pointer && adapterValue.IsNil() {
+		tb.Errorf("trtest: adapter %T is nil, WithTx must return the rebound adapter", adapter)
+		return false
+	}
+
+	w := &reboundWalker{
+		txType:  txType,
+		txValue: reflect.ValueOf(tx),
+		visited: map[uintptr]struct{}{},
+	}
+	w.walk(adapterValue, adapterValue.Type().String())
+
+	if w.found == 0 {
+		tb.Errorf("trtest: no fields able to hold %s found in %T", txType, adapter)
+		return false
+	}
+
+	for _, path := range w.unbound {
+		tb.Errorf("trtest: %s is not bound to the transaction", path)
+	}
+
+	return len(w.unbound) == 0
+}
+
+type reboundWalker struct {
+	txType  reflect.Type
+	txValue reflect.Value
+	visited map[uintptr]struct{}
+	found   int
+	unbound []string
+}
+
+func (slf *reboundWalker) walk(v reflect.Value, path string) {
+	//nolint:exhaustive // only containers which may hold repositories are traversed
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+		if _, ok := slf.visited[v.Pointer()]; ok {
+			return
+		}
+		slf.visited[v.Pointer()] = struct{}{}
+
+		slf.walk(v.Elem(), path)
+	case reflect.Struct:
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			fieldPath := path + "." + field.Name
+
+			if slf.holdsTx(field.Type) {
+				slf.check(v.Field(i), fieldPath)
+				continue
+			}
+
+			slf.walk(v.Field(i), fieldPath)
+		}
+	}
+}
+
+func (slf *reboundWalker) holdsTx(t reflect.Type) bool {
+	if t == slf.txType {
+		return true
+	}
+
+	return t.Kind() == reflect.Interface && t.NumMethod() > 0 && slf.txType.Implements(t)
+}
+
+func (slf *reboundWalker) check(v reflect.Value, path string) {
+	slf.found++
+
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			slf.unbound = append(slf.unbound, path)
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() != slf.txType || !sameValue(v, slf.txValue) {
+		slf.unbound = append(slf.unbound, path)
+	}
+}
+
+func sameValue(a, b reflect.Value) bool {
+	//nolint:exhaustive // reference kinds are compared by identity, everything else by equality
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.Func, reflect.Slice, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	default:
+		return a.Comparable() && b.Comparable() && a.Equal(b)
+	}
+}
@@ -0,0 +1,92 @@
+package trtest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/metalfm/transactor/trtest"
+)
+
+type query interface {
+	Exec(q string) error
+}
+
+type fakeDB struct{ name string }
+
+func (f *fakeDB) Exec(string) error { return nil }
+
+type repo struct {
+	q query
+}
+
+type adapter struct {
+	repoUser  *repo
+	repoOrder *repo
+}
+
+type recorder struct {
+	testing.TB
+
+	errs []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+type AssertAllRebound struct {
+	suite.Suite
+
+	db  *fakeDB
+	tx  *fakeDB
+	rec *recorder
+}
+
+func (slf *AssertAllRebound) SetupTest() {
+	slf.db = &fakeDB{name: "db"}
+	slf.tx = &fakeDB{name: "tx"}
+	slf.rec = &recorder{TB: slf.T()}
+}
+
+func (slf *AssertAllRebound) TestAllBound() {
+	a := &adapter{repoUser: &repo{q: slf.tx}, repoOrder: &repo{q: slf.tx}}
+
+	slf.True(trtest.AssertAllRebound(slf.rec, a, slf.tx))
+	slf.Empty(slf.rec.errs)
+}
+
+func (slf *AssertAllRebound) TestForgottenRebind() {
+	a := &adapter{repoUser: &repo{q: slf.tx}, repoOrder: &repo{q: slf.db}}
+
+	slf.False(trtest.AssertAllRebound(slf.rec, a, slf.tx))
+	slf.Equal([]string{"trtest: *trtest_test.adapter.repoOrder.q is not bound to the transaction"}, slf.rec.errs)
+}
+
+func (slf *AssertAllRebound) TestNilRepoQuery() {
+	a := &adapter{repoUser: &repo{q: slf.tx}, repoOrder: &repo{}}
+
+	slf.False(trtest.AssertAllRebound(slf.rec, a, slf.tx))
+	slf.Len(slf.rec.errs, 1)
+}
+
+func (slf *AssertAllRebound) TestNoHolders() {
+	slf.False(trtest.AssertAllRebound(slf.rec, &struct{ n int }{}, slf.tx))
+	slf.Len(slf.rec.errs, 1)
+}
+
+func (slf *AssertAllRebound) TestNilAdapter() {
+	slf.False(trtest.AssertAllRebound(slf.rec, nil, slf.tx))
+	slf.False(trtest.AssertAllRebound(slf.rec, (*adapter)(nil), slf.tx))
+	slf.Equal([]string{
+		"trtest: adapter <nil> is nil, WithTx must return the rebound adapter",
+		"trtest: adapter *trtest_test.adapter is nil, WithTx must return the rebound adapter",
+	}, slf.rec.errs)
+}
+
+func TestAssertAllRebound(t *testing.T) {
+	suite.Run(t, new(AssertAllRebound))
+}
@@ -0,0 +1,104 @@
+package trtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/metalfm/transactor/tr"
+)
+
+// Tape entries written around every transaction.
+const (
+	TapeBegin    = "BEGIN"
+	TapeCommit   = "COMMIT"
+	TapeRollback = "ROLLBACK"
+)
+
+// Recorder collects the calls reported by a recording proxy.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+// Record appends a call of method with args, formatted as Method(arg1, arg2).
+func (slf *Recorder) Record(method string, args ...any) {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = fmt.Sprint(arg)
+	}
+
+	slf.append(method + "(" + strings.Join(parts, ", ") + ")")
+}
+
+// Calls returns a copy of the recorded entries in order.
+func (slf *Recorder) Calls() []string {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	return append([]string(nil), slf.calls...)
+}
+
+// Reset forgets every recorded entry.
+func (slf *Recorder) Reset() {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	slf.calls = nil
+}
+
+func (slf *Recorder) append(entry string) {
+	slf.mu.Lock()
+	defer slf.mu.Unlock()
+
+	slf.calls = append(slf.calls, entry)
+}
+
+type tape[T, P any] struct {
+	*Recorder
+
+	base  tr.Transactor[T]
+	proxy func(repo T, rec *Recorder) P
+}
+
+// Tape is a Transactor recording the repository calls made in its transactions,
+// for asserting the exact call sequence of an orchestration, e.g. against a golden file.
+//
+// proxy wraps the repository bound by base into a recording proxy: a hand-written type
+// implementing the methods the code under test uses, each calling rec.Record and then
+// delegating to repo. Every attempt starts with BEGIN, and the transaction ends with COMMIT
+// or ROLLBACK depending on the error returned by base, so attempts retried by base show up
+// as consecutive BEGIN entries. Nothing is written when base fails before calling back.
+//
+//nolint:revive // exported constructor intentionally returns hidden implementation type
+func Tape[T, P any](base tr.Transactor[T], proxy func(repo T, rec *Recorder) P) *tape[T, P] {
+	return &tape[T, P]{
+		Recorder: &Recorder{},
+		base:     base,
+		proxy:    proxy,
+	}
+}
+
+func (slf *tape[T, P]) InTx(ctx context.Context, fn func(P) error) error {
+	started := false
+
+	err := slf.base.InTx(ctx, func(repo T) error {
+		started = true
+		slf.append(TapeBegin)
+
+		return fn(slf.proxy(repo, slf.Recorder))
+	})
+	if err != nil {
+		if started {
+			slf.append(TapeRollback)
+		}
+
+		return err
+	}
+
+	slf.append(TapeCommit)
+
+	return nil
+}
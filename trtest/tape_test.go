@@ -0,0 +1,89 @@
+package trtest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/metalfm/transactor/trtest"
+	mock_tr "github.com/metalfm/transactor/trtest/mock"
+)
+
+type store struct{}
+
+func (*store) Save(string, int) error { return nil }
+
+type recordingStore struct {
+	store *store
+	rec   *trtest.Recorder
+}
+
+func (slf *recordingStore) Save(name string, n int) error {
+	slf.rec.Record("Save", name, n)
+	return slf.store.Save(name, n)
+}
+
+type Tape struct {
+	suite.Suite
+
+	ctx  context.Context
+	base *mock_tr.MockTransactor[*store]
+	tape interface {
+		InTx(ctx context.Context, fn func(*recordingStore) error) error
+		Calls() []string
+	}
+}
+
+func (slf *Tape) SetupTest() {
+	slf.ctx = context.Background()
+	slf.base = mock_tr.NewMockTransactor[*store](gomock.NewController(slf.T()))
+	slf.tape = trtest.Tape(slf.base, func(s *store, rec *trtest.Recorder) *recordingStore {
+		return &recordingStore{store: s, rec: rec}
+	})
+}
+
+func (slf *Tape) TestCommit() {
+	slf.base.EXPECT().InTx(slf.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*store) error) error {
+			return fn(&store{})
+		})
+
+	err := slf.tape.InTx(slf.ctx, func(s *recordingStore) error {
+		_ = s.Save("a", 1)
+		return s.Save("b", 2)
+	})
+	slf.Require().NoError(err)
+	slf.Equal([]string{"BEGIN", "Save(a, 1)", "Save(b, 2)", "COMMIT"}, slf.tape.Calls())
+}
+
+func (slf *Tape) TestRetriedRollback() {
+	expected := errors.New("conflict")
+	slf.base.EXPECT().InTx(slf.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*store) error) error {
+			_ = fn(&store{})
+			return fn(&store{})
+		})
+
+	err := slf.tape.InTx(slf.ctx, func(s *recordingStore) error {
+		_ = s.Save("a", 1)
+		return expected
+	})
+	slf.Require().ErrorIs(err, expected)
+	slf.Equal([]string{"BEGIN", "Save(a, 1)", "BEGIN", "Save(a, 1)", "ROLLBACK"}, slf.tape.Calls())
+}
+
+func (slf *Tape) TestBeginFailure() {
+	expected := errors.New("begin")
+	slf.base.EXPECT().InTx(slf.ctx, gomock.Any()).Return(expected)
+
+	err := slf.tape.InTx(slf.ctx, func(*recordingStore) error { return nil })
+	slf.Require().ErrorIs(err, expected)
+	slf.Empty(slf.tape.Calls())
+}
+
+func TestTape(t *testing.T) {
+	suite.Run(t, new(Tape))
+}